@@ -0,0 +1,416 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+// DifferenceOf returns the values of a which aren't in b
+func DifferenceOf[T Integer](a, b SetOf[T]) *SizedOf[T] {
+	if x, y, ok := aligned(a, b); ok {
+		return bucketwise(x, y, appendDifference[T])
+	}
+	values := make([]T, 0, a.Len())
+	for value := range a.All() {
+		if b.Exists(value) == false {
+			values = append(values, value)
+		}
+	}
+	return FromSliceOf(values)
+}
+
+// SymmetricDifferenceOf returns the values which are in either a or b, but not both
+func SymmetricDifferenceOf[T Integer](a, b SetOf[T]) *SizedOf[T] {
+	if x, y, ok := aligned(a, b); ok {
+		return bucketwise(x, y, appendSymmetricDifference[T])
+	}
+	values := make([]T, 0, a.Len()+b.Len())
+	for value := range a.All() {
+		if b.Exists(value) == false {
+			values = append(values, value)
+		}
+	}
+	for value := range b.All() {
+		if a.Exists(value) == false {
+			values = append(values, value)
+		}
+	}
+	return FromSliceOf(values)
+}
+
+// IsSubsetOf returns true if every value of a is in b
+func IsSubsetOf[T Integer](a, b SetOf[T]) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	if x, y, ok := aligned(a, b); ok {
+		for i, bucket := range x.buckets {
+			if subset(bucket, y.buckets[i]) == false {
+				return false
+			}
+		}
+		return true
+	}
+	for value := range a.All() {
+		if b.Exists(value) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSupersetOf returns true if every value of b is in a
+func IsSupersetOf[T Integer](a, b SetOf[T]) bool {
+	return IsSubsetOf(b, a)
+}
+
+// DisjointOf returns true if a and b have no value in common
+func DisjointOf[T Integer](a, b SetOf[T]) bool {
+	if x, y, ok := aligned(a, b); ok {
+		for i, bucket := range x.buckets {
+			if disjoint(bucket, y.buckets[i]) == false {
+				return false
+			}
+		}
+		return true
+	}
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	for value := range a.All() {
+		if b.Exists(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualOf returns true if a and b contain the same values
+func EqualOf[T Integer](a, b SetOf[T]) bool {
+	return a.Len() == b.Len() && IsSubsetOf(a, b)
+}
+
+// IntersectWith removes every value of the set which isn't in all of others
+func (s *SizedOf[T]) IntersectWith(others ...SetOf[T]) {
+	for _, other := range others {
+		o, _, ok := aligned[T](other, s)
+		// buckets are only owned, and so possibly copied, once known to shrink
+		for i, bucket := range s.buckets {
+			if ok {
+				if intersectionCount(bucket, o.buckets[i]) == len(bucket) {
+					continue
+				}
+				bucket = keepIntersection(s.own(uint64(i)), o.buckets[i])
+			} else {
+				j := indexExisting(bucket, other, false)
+				if j == len(bucket) {
+					continue
+				}
+				bucket = s.own(uint64(i))
+				bucket = bucket[:j+len(keepExisting(bucket[j:], other, true))]
+			}
+			s.length -= len(s.buckets[i]) - len(bucket)
+			s.buckets[i] = bucket
+		}
+	}
+	s.fit()
+}
+
+// UnionWith adds every value of others to the set
+func (s *SizedOf[T]) UnionWith(others ...SetOf[T]) {
+	for _, other := range others {
+		o, _, ok := aligned[T](other, s)
+		if ok == false {
+			for value := range other.All() {
+				if s.insert(s.bucket(value), value) {
+					s.length++
+				}
+			}
+			continue
+		}
+		// buckets are only owned, and so possibly copied, once known to grow
+		for i, bucket := range o.buckets {
+			added := len(bucket) - intersectionCount(s.buckets[i], bucket)
+			if added == 0 {
+				continue
+			}
+			s.buckets[i] = unionInto(s.own(uint64(i)), bucket, added, s.growBy)
+			s.length += added
+		}
+	}
+	s.fit()
+}
+
+// DifferenceWith removes every value of others from the set
+func (s *SizedOf[T]) DifferenceWith(others ...SetOf[T]) {
+	for _, other := range others {
+		o, _, ok := aligned[T](other, s)
+		// buckets are only owned, and so possibly copied, once known to shrink
+		for i, bucket := range s.buckets {
+			if ok {
+				if intersectionCount(bucket, o.buckets[i]) == 0 {
+					continue
+				}
+				bucket = keepDifference(s.own(uint64(i)), o.buckets[i])
+			} else {
+				j := indexExisting(bucket, other, true)
+				if j == len(bucket) {
+					continue
+				}
+				bucket = s.own(uint64(i))
+				bucket = bucket[:j+len(keepExisting(bucket[j:], other, false))]
+			}
+			s.length -= len(s.buckets[i]) - len(bucket)
+			s.buckets[i] = bucket
+		}
+	}
+	s.fit()
+}
+
+// Difference returns the values of a which aren't in b
+func Difference(a, b Set) *Sized {
+	return DifferenceOf(a, b)
+}
+
+// SymmetricDifference returns the values which are in either a or b, but not both
+func SymmetricDifference(a, b Set) *Sized {
+	return SymmetricDifferenceOf(a, b)
+}
+
+// IsSubset returns true if every value of a is in b
+func IsSubset(a, b Set) bool {
+	return IsSubsetOf(a, b)
+}
+
+// IsSuperset returns true if every value of b is in a
+func IsSuperset(a, b Set) bool {
+	return IsSupersetOf(a, b)
+}
+
+// Disjoint returns true if a and b have no value in common
+func Disjoint(a, b Set) bool {
+	return DisjointOf(a, b)
+}
+
+// Equal returns true if a and b contain the same values
+func Equal(a, b Set) bool {
+	return EqualOf(a, b)
+}
+
+// aligned returns a and b as SizedOf if value v is in bucket i of both, in
+// which case operations can be done bucket by bucket without any lookup
+func aligned[T Integer](a, b SetOf[T]) (*SizedOf[T], *SizedOf[T], bool) {
+	x, ok := a.(*SizedOf[T])
+	if ok == false {
+		return nil, nil, false
+	}
+	y, ok := b.(*SizedOf[T])
+	if ok == false {
+		return nil, nil, false
+	}
+	return x, y, x.mask == y.mask && x.hash == nil && y.hash == nil
+}
+
+// bucketwise returns a set configured like a, whose bucket i is the result of
+// merging bucket i of a and b. All the buckets share a single backing array.
+func bucketwise[T Integer](a, b *SizedOf[T], merge func(dst, a, b []T) []T) *SizedOf[T] {
+	offsets := make([]int, len(a.buckets)+1)
+	var values []T
+	for i, bucket := range a.buckets {
+		values = merge(values, bucket, b.buckets[i])
+		offsets[i+1] = len(values)
+	}
+	return a.fill(values, offsets)
+}
+
+// keepIntersection filters sorted a, in place, to the values also in sorted b
+func keepIntersection[T Integer](a, b []T) []T {
+	n, j := 0, 0
+	for _, value := range a {
+		for j < len(b) && b[j] < value {
+			j++
+		}
+		if j < len(b) && b[j] == value {
+			a[n] = value
+			n++
+		}
+	}
+	return a[:n]
+}
+
+// keepDifference filters sorted a, in place, to the values not in sorted b
+func keepDifference[T Integer](a, b []T) []T {
+	n, j := 0, 0
+	for _, value := range a {
+		for j < len(b) && b[j] < value {
+			j++
+		}
+		if j == len(b) || b[j] != value {
+			a[n] = value
+			n++
+		}
+	}
+	return a[:n]
+}
+
+// keepExisting filters a, in place, to the values whose existence in set is exists
+func keepExisting[T Integer](a []T, set SetOf[T], exists bool) []T {
+	n := 0
+	for _, value := range a {
+		if set.Exists(value) == exists {
+			a[n] = value
+			n++
+		}
+	}
+	return a[:n]
+}
+
+// indexExisting returns the index of the first value of a whose existence in
+// set is exists, or len(a) if there's none
+func indexExisting[T Integer](a []T, set SetOf[T], exists bool) int {
+	for i, value := range a {
+		if set.Exists(value) == exists {
+			return i
+		}
+	}
+	return len(a)
+}
+
+// unionInto merges sorted b, of which added values aren't in a, into sorted
+// a, in place when a has the capacity
+func unionInto[T Integer](a, b []T, added, growBy int) []T {
+	n := len(a) + added
+	var dst []T
+	if cap(a) >= n {
+		dst = a[:n]
+	} else {
+		dst = make([]T, n, n+growBy)
+	}
+	// merge from the back so that, in place, no unread value is overwritten
+	i, j := len(a)-1, len(b)-1
+	for k := n - 1; j >= 0; k-- {
+		switch {
+		case i >= 0 && a[i] > b[j]:
+			dst[k] = a[i]
+			i--
+		case i >= 0 && a[i] == b[j]:
+			dst[k] = a[i]
+			i--
+			j--
+		default:
+			dst[k] = b[j]
+			j--
+		}
+	}
+	if i >= 0 && &dst[0] != &a[0] {
+		copy(dst, a[:i+1])
+	}
+	return dst
+}
+
+// intersectionCount returns the number of values in both sorted a and b
+func intersectionCount[T Integer](a, b []T) int {
+	count, i, j := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			count++
+			i++
+			j++
+		}
+	}
+	return count
+}
+
+// appendDifference appends the values of sorted a which aren't in sorted b
+func appendDifference[T Integer](dst, a, b []T) []T {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			dst = append(dst, a[i])
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return append(dst, a[i:]...)
+}
+
+// appendUnion appends the values in either sorted a or b, in order
+func appendUnion[T Integer](dst, a, b []T) []T {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			dst = append(dst, a[i])
+			i++
+		case a[i] > b[j]:
+			dst = append(dst, b[j])
+			j++
+		default:
+			dst = append(dst, a[i])
+			i++
+			j++
+		}
+	}
+	dst = append(dst, a[i:]...)
+	return append(dst, b[j:]...)
+}
+
+// appendSymmetricDifference appends the values in only one of sorted a and b, in order
+func appendSymmetricDifference[T Integer](dst, a, b []T) []T {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			dst = append(dst, a[i])
+			i++
+		case a[i] > b[j]:
+			dst = append(dst, b[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	dst = append(dst, a[i:]...)
+	return append(dst, b[j:]...)
+}
+
+// subset returns true if every value of sorted a is in sorted b
+func subset[T Integer](a, b []T) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	j := 0
+	for _, value := range a {
+		for j < len(b) && b[j] < value {
+			j++
+		}
+		if j == len(b) || b[j] != value {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// disjoint returns true if sorted a and b have no value in common
+func disjoint[T Integer](a, b []T) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,259 @@
+package intset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_Difference(t *testing.T) {
+	a, b := pair(NewSized(10), NewSized(10))
+	for _, s := range []*Sized{Difference(a, b), Difference(a.Freeze(), b)} {
+		AssertEqual(t, s.Len(), 2)
+		AssertTrue(t, s.Exists(1))
+		AssertTrue(t, s.Exists(2))
+		AssertFalse(t, s.Exists(3))
+	}
+}
+
+func Test_SymmetricDifference(t *testing.T) {
+	a, b := pair(NewSized(10), NewSized(10))
+	for _, s := range []*Sized{SymmetricDifference(a, b), SymmetricDifference(a, b.Freeze())} {
+		AssertEqual(t, s.Len(), 4)
+		AssertTrue(t, s.Exists(1))
+		AssertTrue(t, s.Exists(5))
+		AssertFalse(t, s.Exists(3))
+	}
+}
+
+func Test_Subset(t *testing.T) {
+	a, b := pair(NewSized32(10), NewSized32(10))
+	AssertFalse(t, IsSubset32(a, b))
+	AssertFalse(t, IsSuperset32(a, b))
+	b.Set(1)
+	b.Set(2)
+	AssertTrue(t, IsSubset32(a, b))
+	AssertTrue(t, IsSubset32(a.Freeze(), b))
+	AssertTrue(t, IsSuperset32(b, a))
+	AssertFalse(t, IsSubset32(b, a))
+	AssertTrue(t, IsSubset32(NewSized32(10), a))
+}
+
+func Test_Disjoint(t *testing.T) {
+	a, b := pair(NewRune(10), NewRune(10))
+	AssertFalse(t, DisjointRune(a, b))
+	AssertFalse(t, DisjointRune(a.Freeze(), b))
+	b.Remove(3)
+	b.Remove(4)
+	AssertTrue(t, DisjointRune(a, b))
+	AssertTrue(t, DisjointRune(a, b.Freeze()))
+}
+
+func Test_Equal(t *testing.T) {
+	a, b := pair(NewSized(10), NewSized(1000))
+	AssertFalse(t, Equal(a, b))
+	b.Set(1)
+	b.Set(2)
+	a.Set(5)
+	a.Set(6)
+	AssertTrue(t, Equal(a, b))
+	AssertTrue(t, Equal(a.Freeze(), b))
+	AssertTrue(t, EqualRune(NewRune(10), NewRune(100)))
+}
+
+// the bucket-aligned and generic paths agree with each other
+func Test_Algebra_MatchesGenericPath(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for round := 0; round < 50; round++ {
+		a, b := NewSized(64), NewSized(64)
+		for i := 0; i < 100; i++ {
+			a.Set(r.Intn(300))
+			b.Set(r.Intn(300))
+		}
+		fa, fb := a.Freeze(), b.Freeze()
+		AssertTrue(t, Equal(Difference(a, b), Difference(fa, fb)))
+		AssertTrue(t, Equal(SymmetricDifference(a, b), SymmetricDifference(fa, fb)))
+		AssertEqual(t, IsSubset(a, b), IsSubset(fa, fb))
+		AssertEqual(t, Disjoint(a, b), Disjoint(fa, fb))
+
+		d := Difference(a, b)
+		AssertTrue(t, IsSubset(d, a))
+		AssertTrue(t, Disjoint(d, b))
+		AssertEqual(t, d.Len()+Intersect(a, b).Len(), a.Len())
+		x := SymmetricDifference(a, b)
+		AssertEqual(t, x.Len(), d.Len()+Difference(b, a).Len())
+
+		// results are usable sets
+		d.Set(1000)
+		d.Remove(1000)
+		AssertTrue(t, Equal(d, Difference(fa, fb)))
+	}
+}
+
+// pair returns a set with 1, 2, 3, 4 and one with 3, 4, 5, 6
+func pair[T Integer](a, b *SizedOf[T]) (*SizedOf[T], *SizedOf[T]) {
+	for i := T(1); i <= 4; i++ {
+		a.Set(i)
+		b.Set(i + 2)
+	}
+	return a, b
+}
+
+func Test_IntersectWith(t *testing.T) {
+	a, b := pair(NewSized(10), NewSized(10))
+	c := NewSized(10)
+	c.Set(4)
+	c.Set(3)
+	a.IntersectWith(b, c.Freeze())
+	AssertEqual(t, a.Len(), 2)
+	AssertTrue(t, a.Exists(3))
+	AssertTrue(t, a.Exists(4))
+
+	c.Remove(3)
+	a.IntersectWith(c)
+	AssertEqual(t, a.Len(), 1)
+	AssertTrue(t, a.Exists(4))
+}
+
+func Test_UnionWith(t *testing.T) {
+	a, b := pair(NewSized32(10), NewSized32(10))
+	c := NewSized32(1000)
+	c.Set(100)
+	a.UnionWith(b, c)
+	AssertEqual(t, a.Len(), 7)
+	for _, value := range []uint32{1, 2, 3, 4, 5, 6, 100} {
+		AssertTrue(t, a.Exists(value))
+	}
+}
+
+func Test_DifferenceWith(t *testing.T) {
+	a, b := pair(NewRune(10), NewRune(10))
+	a.DifferenceWith(b.Freeze())
+	AssertEqual(t, a.Len(), 2)
+	AssertTrue(t, a.Exists(1))
+	AssertTrue(t, a.Exists(2))
+	a.DifferenceWith(a)
+	AssertEqual(t, a.Len(), 0)
+}
+
+func Test_UnionWith_ReusesCapacity(t *testing.T) {
+	a := NewSizedConfig(4, NewConfig().BucketGrowBy(8))
+	b := NewSized(4)
+	a.Set(1)
+	a.Set(5)
+	b.Set(3)
+	b.Set(5)
+	before := &a.buckets[0][0]
+	a.UnionWith(b)
+	AssertTrue(t, before == &a.buckets[0][0])
+	assertSequence(t, a.buckets[0], []int{1, 3, 5})
+}
+
+func Test_InPlace_GrowsAndShrinks(t *testing.T) {
+	a := NewSizedConfig(4, NewConfig().AutoGrow(4).AutoShrink(1))
+	b := NewSized(1000)
+	for i := 0; i < 1000; i++ {
+		b.Set(i)
+	}
+	a.UnionWith(b)
+	AssertEqual(t, len(a.buckets), 256)
+	AssertEqual(t, a.Len(), 1000)
+
+	c := NewSized(4)
+	c.Set(7)
+	a.IntersectWith(c)
+	AssertEqual(t, len(a.buckets), 1)
+	AssertTrue(t, a.Exists(7))
+}
+
+func Test_InPlace_DoesNotModifyAtomicSnapshots(t *testing.T) {
+	a := NewAtomic(16)
+	a.Update(func(s *Sized) {
+		for i := 0; i < 16; i++ {
+			s.Set(i)
+		}
+	})
+	before := a.Snapshot()
+	other := NewSized(16)
+	other.Set(3)
+	other.Set(100)
+	a.Update(func(s *Sized) {
+		s.UnionWith(other)
+		s.DifferenceWith(other)
+		s.IntersectWith(before)
+	})
+	AssertEqual(t, before.Len(), 16)
+	for i := 0; i < 16; i++ {
+		AssertTrue(t, before.Exists(i))
+	}
+	AssertEqual(t, a.Len(), 15)
+	AssertFalse(t, a.Exists(3))
+}
+
+func Test_InPlace_OnlyCopiesShrinkingBuckets(t *testing.T) {
+	a := NewSized(16)
+	for i := 0; i < 64; i++ {
+		a.Set(i)
+	}
+	superset := Union(a, FromSlice([]int{100}))
+	disjoint := FromSlice([]int{100})
+	subset := a.like()
+	subset.Set(1)
+	subset.Set(2)
+	for _, others := range [][3]Set{{superset, disjoint, subset}, {superset.Freeze(), disjoint.Freeze(), subset.Freeze()}} {
+		s := a.clone()
+		s.IntersectWith(others[0])
+		s.DifferenceWith(others[1])
+		s.UnionWith(others[2])
+		AssertEqual(t, s.Len(), 64)
+		for i, bucket := range s.buckets {
+			AssertTrue(t, s.shared[i])
+			AssertTrue(t, &bucket[0] == &a.buckets[i][0])
+		}
+	}
+
+	s := a.clone()
+	s.DifferenceWith(FromSlice([]int{5}).Freeze())
+	AssertEqual(t, s.Len(), 63)
+	AssertEqual(t, a.Len(), 64)
+	for i := range s.buckets {
+		AssertEqual(t, s.shared[i], uint64(i) != s.bucket(5))
+	}
+	s.UnionWith(FromSlice([]int{5, 6}))
+	AssertEqual(t, s.Len(), 64)
+	AssertEqual(t, a.Len(), 64)
+	for i := range s.buckets {
+		AssertEqual(t, s.shared[i], uint64(i) != s.bucket(5))
+	}
+}
+
+// in-place operations agree with their allocating equivalents
+func Test_InPlace_MatchesAllocating(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for round := 0; round < 50; round++ {
+		a, b := NewSized(64), NewSized(64)
+		for i := 0; i < 100; i++ {
+			a.Set(r.Intn(300))
+			b.Set(r.Intn(300))
+		}
+		for _, other := range []Set{b, b.Freeze()} {
+			s := copyOf(a)
+			s.UnionWith(other)
+			AssertTrue(t, Equal(s, Union(a, b)))
+
+			s = copyOf(a)
+			s.IntersectWith(other)
+			AssertTrue(t, Equal(s, Intersect(a, b)))
+
+			s = copyOf(a)
+			s.DifferenceWith(other)
+			AssertTrue(t, Equal(s, Difference(a, b)))
+		}
+	}
+}
+
+// copyOf returns a copy of s with the same buckets
+func copyOf[T Integer](s *SizedOf[T]) *SizedOf[T] {
+	c := s.like()
+	c.UnionWith(s)
+	return c
+}
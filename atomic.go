@@ -0,0 +1,98 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicOf is a set which is safe for concurrent use, optimized for reads.
+// Readers never take a lock, they probe an immutable SizedOf. Writers build
+// a modified copy, which shares every unchanged bucket with the original, and
+// atomically swap it in.
+type AtomicOf[T Integer] struct {
+	set atomic.Pointer[SizedOf[T]]
+	mu  sync.Mutex
+}
+
+// Atomic is an int set with lock-free reads
+type Atomic = AtomicOf[int]
+
+// Atomic32 is a uint32 set with lock-free reads
+type Atomic32 = AtomicOf[uint32]
+
+// AtomicRune is a rune set with lock-free reads
+type AtomicRune = AtomicOf[rune]
+
+// NewAtomicOf creates an empty atomic set with target capacity specified by size using default configuration
+func NewAtomicOf[T Integer](size int) *AtomicOf[T] {
+	return NewAtomicOfConfig[T](size, Default)
+}
+
+// NewAtomicOfConfig creates an empty atomic set with target capacity specified by size
+func NewAtomicOfConfig[T Integer](size int, config *Config) *AtomicOf[T] {
+	a := &AtomicOf[T]{}
+	a.set.Store(NewSizedOfConfig[T](size, config))
+	return a
+}
+
+// NewAtomic creates an empty atomic int set with target capacity specified by size using default configuration
+func NewAtomic(size int) *Atomic {
+	return NewAtomicOf[int](size)
+}
+
+// NewAtomicConfig creates an empty atomic int set with target capacity specified by size
+func NewAtomicConfig(size int, config *Config) *Atomic {
+	return NewAtomicOfConfig[int](size, config)
+}
+
+// NewAtomic32 creates an empty atomic uint32 set with target capacity specified by size using default configuration
+func NewAtomic32(size uint32) *Atomic32 {
+	return NewAtomicOf[uint32](int(size))
+}
+
+// NewAtomic32Config creates an empty atomic uint32 set with target capacity specified by size
+func NewAtomic32Config(size uint32, config *Config) *Atomic32 {
+	return NewAtomicOfConfig[uint32](int(size), config)
+}
+
+// NewAtomicRune creates an empty atomic rune set with target capacity specified by size using default configuration
+func NewAtomicRune(size rune) *AtomicRune {
+	return NewAtomicOf[rune](int(size))
+}
+
+// NewAtomicRuneConfig creates an empty atomic rune set with target capacity specified by size
+func NewAtomicRuneConfig(size rune, config *Config) *AtomicRune {
+	return NewAtomicOfConfig[rune](int(size), config)
+}
+
+// Exists returns true if the value exists in the set
+func (a *AtomicOf[T]) Exists(value T) bool {
+	return a.set.Load().Exists(value)
+}
+
+// Len returns the total number of elements in the set
+func (a *AtomicOf[T]) Len() int {
+	return a.set.Load().Len()
+}
+
+// Each iterates through a snapshot of the set items and applies function f to each set item
+func (a *AtomicOf[T]) Each(f func(value T)) {
+	a.set.Load().Each(f)
+}
+
+// Snapshot returns the current version of the set. It must not be modified.
+func (a *AtomicOf[T]) Snapshot() *SizedOf[T] {
+	return a.set.Load()
+}
+
+// Update calls f with a copy of the current set and, once f returns, makes
+// that copy the current set. Updates are serialized, readers see either every
+// change made by f or none of them.
+func (a *AtomicOf[T]) Update(f func(set *SizedOf[T])) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	set := a.set.Load().clone()
+	f(set)
+	a.set.Store(set)
+}
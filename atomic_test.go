@@ -0,0 +1,119 @@
+package intset
+
+import (
+	"sync"
+	"testing"
+)
+
+var (
+	_ Set     = (*Atomic)(nil)
+	_ Set32   = (*Atomic32)(nil)
+	_ SetRune = (*AtomicRune)(nil)
+)
+
+func Test_Atomic_Updates(t *testing.T) {
+	a := NewAtomic(100)
+	a.Update(func(s *Sized) {
+		for i := 0; i < 100; i++ {
+			s.Set(i)
+		}
+	})
+	AssertEqual(t, a.Len(), 100)
+	AssertTrue(t, a.Exists(99))
+	AssertFalse(t, a.Exists(100))
+}
+
+func Test_Atomic_SnapshotsAreImmutable(t *testing.T) {
+	a := NewAtomic32Config(16, NewConfig().BucketGrowBy(4))
+	a.Update(func(s *Sized32) {
+		for i := uint32(0); i < 16; i++ {
+			s.Set(i)
+		}
+	})
+	before := a.Snapshot()
+	a.Update(func(s *Sized32) {
+		s.Remove(0)
+		s.Remove(5)
+		s.Set(100)
+		s.Set(104)
+	})
+	after := a.Snapshot()
+
+	AssertEqual(t, before.Len(), 16)
+	for i := uint32(0); i < 16; i++ {
+		AssertTrue(t, before.Exists(i))
+	}
+	AssertFalse(t, before.Exists(100))
+
+	AssertEqual(t, after.Len(), 16)
+	AssertFalse(t, after.Exists(0))
+	AssertFalse(t, after.Exists(5))
+	AssertTrue(t, after.Exists(100))
+	AssertTrue(t, after.Exists(104))
+
+	// untouched buckets are shared
+	AssertTrue(t, &before.buckets[2][0] == &after.buckets[2][0])
+	AssertFalse(t, &before.buckets[0][0] == &after.buckets[0][0])
+}
+
+func Test_Atomic_SnapshotsSurviveResize(t *testing.T) {
+	a := NewAtomicRuneConfig(4, NewConfig().AutoGrow(2))
+	a.Update(func(s *Rune) {
+		s.Set(1)
+	})
+	before := a.Snapshot()
+	a.Update(func(s *Rune) {
+		for i := rune(0); i < 100; i++ {
+			s.Set(i)
+		}
+	})
+	AssertEqual(t, before.Len(), 1)
+	AssertEqual(t, a.Len(), 100)
+	AssertTrue(t, len(a.Snapshot().buckets) > len(before.buckets))
+}
+
+func Test_Atomic_ParallelReadersAndWriters(t *testing.T) {
+	a := NewAtomic(1000)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				a.Update(func(s *Sized) {
+					s.Set(i*4 + w)
+				})
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				a.Exists(i)
+				snapshot := a.Snapshot()
+				count := 0
+				snapshot.Each(func(int) { count++ })
+				if count != snapshot.Len() {
+					t.Error("inconsistent snapshot")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	AssertEqual(t, a.Len(), 400)
+}
+
+func Benchmark_AtomicDenseExists(b *testing.B) {
+	a := NewAtomic(1000000)
+	a.Update(func(s *Sized) {
+		for i := 0; i < 1000000; i++ {
+			s.Set(i)
+		}
+	})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Exists(i % 1000000)
+	}
+}
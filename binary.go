@@ -0,0 +1,299 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// The binary format is little endian:
+//
+//	magic       [4]byte "iset"
+//	version     uint8
+//	width       uint8   bytes per value
+//	flags       uint8   flagSigned | flagHashed
+//	reserved    uint8
+//	growBy      uint32
+//	bucketSize  uint32
+//	buckets     uint64  number of buckets (mask + 1)
+//	length      uint64  number of values
+//	lengths     [buckets]uint32
+//	values      [length]width, bucket by bucket
+const (
+	binaryVersion    = 1
+	binaryHeaderSize = 32
+	flagSigned       = 1
+	flagHashed       = 2
+	// maxPrealloc caps how many values are allocated up front from a header,
+	// and how many a bucket decoded from one grows by
+	maxPrealloc = 1 << 20
+)
+
+var binaryMagic = [4]byte{'i', 's', 'e', 't'}
+
+var (
+	// ErrInvalidFormat is returned when decoding data which isn't a valid set
+	ErrInvalidFormat = errors.New("intset: invalid format")
+	// ErrUnsupportedVersion is returned when decoding data written by a newer version
+	ErrUnsupportedVersion = errors.New("intset: unsupported version")
+	// ErrValueOverflow is returned when a decoded value doesn't fit in the set's type
+	ErrValueOverflow = errors.New("intset: value overflows set type")
+	// ErrHasherMismatch is returned when decoding a set whose layout depends on a
+	// Hasher into a set without one, or vice versa
+	ErrHasherMismatch = errors.New("intset: hasher mismatch")
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (s *SizedOf[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(binaryHeaderSize + len(s.buckets)*4 + s.length*bitsOf[T]()/8)
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The bucket layout is
+// restored as-is. If the set was built with a Hasher, the receiver must be
+// created with a config using the same Hasher. Since the data can't be
+// trusted, a BucketGrowBy above 1<<20 is rejected.
+func (s *SizedOf[T]) UnmarshalBinary(data []byte) error {
+	if len(data) >= binaryHeaderSize && data[4] == binaryVersion {
+		if size, ok := binarySize(data); ok == false || size > uint64(len(data)) {
+			return ErrInvalidFormat
+		}
+	}
+	r := bytes.NewReader(data)
+	if _, err := s.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return ErrInvalidFormat
+	}
+	return nil
+}
+
+// WriteTo implements io.WriterTo
+func (s *SizedOf[T]) WriteTo(w io.Writer) (int64, error) {
+	width := bitsOf[T]() / 8
+	var flags byte
+	if isSigned[T]() {
+		flags |= flagSigned
+	}
+	if s.hash != nil {
+		flags |= flagHashed
+	}
+
+	header := make([]byte, binaryHeaderSize+len(s.buckets)*4)
+	copy(header, binaryMagic[:])
+	header[4] = binaryVersion
+	header[5] = byte(width)
+	header[6] = flags
+	binary.LittleEndian.PutUint32(header[8:], uint32(s.growBy))
+	binary.LittleEndian.PutUint32(header[12:], uint32(s.bucketSize))
+	binary.LittleEndian.PutUint64(header[16:], uint64(len(s.buckets)))
+	binary.LittleEndian.PutUint64(header[24:], uint64(s.length))
+	for i, bucket := range s.buckets {
+		binary.LittleEndian.PutUint32(header[binaryHeaderSize+i*4:], uint32(len(bucket)))
+	}
+
+	n, err := w.Write(header)
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	chunk := make([]byte, 0, 4096)
+	for _, bucket := range s.buckets {
+		for _, value := range bucket {
+			if len(chunk)+width > cap(chunk) {
+				n, err = w.Write(chunk)
+				total += int64(n)
+				if err != nil {
+					return total, err
+				}
+				chunk = chunk[:0]
+			}
+			chunk = appendValue(chunk, value, width)
+		}
+	}
+	n, err = w.Write(chunk)
+	return total + int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, replacing the content of the set. See
+// UnmarshalBinary.
+func (s *SizedOf[T]) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	header := make([]byte, binaryHeaderSize)
+	n, err := io.ReadFull(r, header)
+	total += int64(n)
+	if err != nil {
+		return total, unexpected(err)
+	}
+	if bytes.Equal(header[:4], binaryMagic[:]) == false {
+		return total, ErrInvalidFormat
+	}
+	if header[4] != binaryVersion {
+		return total, ErrUnsupportedVersion
+	}
+	width, flags := int(header[5]), header[6]
+	if width != 1 && width != 2 && width != 4 && width != 8 {
+		return total, ErrInvalidFormat
+	}
+	if (flags&flagHashed != 0) != (s.hash != nil) {
+		return total, ErrHasherMismatch
+	}
+	growBy := binary.LittleEndian.Uint32(header[8:])
+	bucketSize := binary.LittleEndian.Uint32(header[12:])
+	count := binary.LittleEndian.Uint64(header[16:])
+	length := binary.LittleEndian.Uint64(header[24:])
+	if count == 0 || count&(count-1) != 0 || bucketSize == 0 {
+		return total, ErrInvalidFormat
+	}
+	if count > math.MaxInt || length > math.MaxInt || growBy > maxPrealloc {
+		return total, ErrInvalidFormat
+	}
+
+	// the header can't be trusted, so nothing is sized from it up front:
+	// lengths and values are read in chunks and grow with the data actually read
+	chunk := make([]byte, 4096)
+	lengths := make([]uint32, 0, min(count, uint64(len(chunk)/4)))
+	var sum uint64
+	for remaining := count; remaining > 0; {
+		batch := min(remaining, uint64(len(chunk)/4))
+		n, err = io.ReadFull(r, chunk[:batch*4])
+		total += int64(n)
+		if err != nil {
+			return total, unexpected(err)
+		}
+		for k := uint64(0); k < batch; k++ {
+			size := binary.LittleEndian.Uint32(chunk[k*4:])
+			lengths = append(lengths, size)
+			sum += uint64(size)
+		}
+		if sum > length {
+			return total, ErrInvalidFormat
+		}
+		remaining -= batch
+	}
+	if sum != length {
+		return total, ErrInvalidFormat
+	}
+
+	l := &SizedOf[T]{
+		mask:    count - 1,
+		buckets: make([][]T, count),
+		hash:    s.hash,
+	}
+	values := make([]T, 0, min(length, maxPrealloc))
+	signed := flags&flagSigned != 0
+	for i, size := range lengths {
+		start := len(values)
+		for remaining := int(size); remaining > 0; {
+			batch := min(remaining, len(chunk)/width)
+			n, err = io.ReadFull(r, chunk[:batch*width])
+			total += int64(n)
+			if err != nil {
+				return total, unexpected(err)
+			}
+			for k := 0; k < batch; k++ {
+				value, ok := decodeValue[T](chunk[k*width:], width, signed)
+				if ok == false {
+					return total, ErrValueOverflow
+				}
+				// buckets must be sorted and hold only their own values
+				if (len(values) > start && value <= values[len(values)-1]) || l.bucket(value) != uint64(i) {
+					return total, ErrInvalidFormat
+				}
+				values = append(values, value)
+			}
+			remaining -= batch
+		}
+	}
+	position := 0
+	for i, size := range lengths {
+		end := position + int(size)
+		l.buckets[i] = values[position:end:end]
+		position = end
+	}
+
+	s.mask = l.mask
+	s.buckets = l.buckets
+	s.shared = nil
+	s.length = int(length)
+	s.growBy = int(growBy)
+	s.bucketSize = int(bucketSize)
+	s.thresholds()
+	return total, nil
+}
+
+// binarySize returns the number of bytes of an encoded set according to its
+// header, or false if that number overflows
+func binarySize(header []byte) (uint64, bool) {
+	width := uint64(header[5])
+	count := binary.LittleEndian.Uint64(header[16:])
+	length := binary.LittleEndian.Uint64(header[24:])
+	hi1, lengths := bits.Mul64(count, 4)
+	hi2, values := bits.Mul64(length, width)
+	size, carry1 := bits.Add64(binaryHeaderSize, lengths, 0)
+	size, carry2 := bits.Add64(size, values, 0)
+	return size, hi1|hi2|carry1|carry2 == 0
+}
+
+// isSigned returns true if T is a signed integer type
+func isSigned[T Integer]() bool {
+	var zero T
+	return ^zero < 0
+}
+
+func appendValue[T Integer](b []byte, value T, width int) []byte {
+	l := len(b)
+	b = b[:l+width]
+	switch width {
+	case 1:
+		b[l] = byte(value)
+	case 2:
+		binary.LittleEndian.PutUint16(b[l:], uint16(value))
+	case 4:
+		binary.LittleEndian.PutUint32(b[l:], uint32(value))
+	default:
+		binary.LittleEndian.PutUint64(b[l:], uint64(value))
+	}
+	return b
+}
+
+// decodeValue reads a width byte value, returning false if it doesn't fit in T
+func decodeValue[T Integer](b []byte, width int, signed bool) (T, bool) {
+	var raw uint64
+	switch width {
+	case 1:
+		raw = uint64(b[0])
+	case 2:
+		raw = uint64(binary.LittleEndian.Uint16(b))
+	case 4:
+		raw = uint64(binary.LittleEndian.Uint32(b))
+	default:
+		raw = binary.LittleEndian.Uint64(b)
+	}
+	if signed {
+		shift := 64 - width*8
+		v := int64(raw<<shift) >> shift
+		t := T(v)
+		return t, int64(t) == v && (t < 0) == (v < 0)
+	}
+	t := T(raw)
+	return t, uint64(t) == raw && t >= 0
+}
+
+// unexpected converts a premature EOF into ErrInvalidFormat
+func unexpected(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrInvalidFormat
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+package intset
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Sized)(nil)
+	_ encoding.BinaryUnmarshaler = (*Sized32)(nil)
+	_ io.WriterTo                = (*Rune)(nil)
+	_ io.ReaderFrom              = (*Rune)(nil)
+)
+
+func Test_Binary_RoundTripsSized(t *testing.T) {
+	s := NewSizedConfig(100, NewConfig().BucketSize(8).BucketGrowBy(2))
+	for i := -50; i < 150; i++ {
+		s.Set(i * 7)
+	}
+	data, err := s.MarshalBinary()
+	AssertTrue(t, err == nil)
+
+	var l Sized
+	AssertTrue(t, l.UnmarshalBinary(data) == nil)
+	AssertSameLayout(t, &l, s)
+	AssertEqual(t, l.growBy, 2)
+	AssertEqual(t, l.bucketSize, 8)
+	l.Set(1)
+	AssertTrue(t, l.Exists(1))
+	AssertTrue(t, l.Exists(-350))
+}
+
+func Test_Binary_RoundTripsSized32(t *testing.T) {
+	s := NewSized32(1000)
+	for i := uint32(0); i < 1000; i++ {
+		s.Set(i * 4099)
+	}
+	var buf bytes.Buffer
+	n, err := s.WriteTo(&buf)
+	AssertTrue(t, err == nil)
+	AssertEqual(t, n, int64(buf.Len()))
+
+	l := NewSized32(1)
+	n, err = l.ReadFrom(&buf)
+	AssertTrue(t, err == nil)
+	AssertEqual(t, buf.Len(), 0)
+	AssertEqual(t, n, int64(binaryHeaderSize+len(s.buckets)*4+4000))
+	AssertSameLayout(t, l, s)
+}
+
+func Test_Binary_RoundTripsRuneWithHasher(t *testing.T) {
+	config := NewConfig().Hasher(XXHash)
+	s := NewRuneConfig(100, config)
+	for i := rune(0); i < 100; i++ {
+		s.Set(i * 256)
+	}
+	data, err := s.MarshalBinary()
+	AssertTrue(t, err == nil)
+
+	AssertTrue(t, NewRune(10).UnmarshalBinary(data) == ErrHasherMismatch)
+	l := NewRuneConfig(0, config)
+	AssertTrue(t, l.UnmarshalBinary(data) == nil)
+	AssertSameLayout(t, l, s)
+}
+
+func Test_Binary_DecodesAcrossTypes(t *testing.T) {
+	s := NewSized32(10)
+	s.Set(1)
+	s.Set(1 << 31)
+	data, _ := s.MarshalBinary()
+
+	var l Sized
+	AssertTrue(t, l.UnmarshalBinary(data) == nil)
+	AssertTrue(t, l.Exists(1))
+	AssertTrue(t, l.Exists(1<<31))
+
+	var r Rune
+	AssertTrue(t, r.UnmarshalBinary(data) == ErrValueOverflow)
+}
+
+func Test_Binary_RejectsInvalidData(t *testing.T) {
+	s := NewSized(10)
+	s.Set(3)
+	s.Set(9)
+	data, _ := s.MarshalBinary()
+
+	var l Sized
+	AssertTrue(t, l.UnmarshalBinary(nil) == ErrInvalidFormat)
+	AssertTrue(t, l.UnmarshalBinary(data[:len(data)-1]) == ErrInvalidFormat)
+	AssertTrue(t, l.UnmarshalBinary(append(data, 0)) == ErrInvalidFormat)
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[4] = 9
+	AssertTrue(t, l.UnmarshalBinary(corrupt) == ErrUnsupportedVersion)
+
+	// 3 and 9 share a bucket, 8 doesn't belong in it
+	corrupt = append([]byte(nil), data...)
+	corrupt[len(corrupt)-8] = 8
+	AssertTrue(t, l.UnmarshalBinary(corrupt) == ErrInvalidFormat)
+
+	// buckets must be sorted
+	corrupt[len(corrupt)-8] = 1
+	AssertTrue(t, l.UnmarshalBinary(corrupt) == ErrInvalidFormat)
+}
+
+func Test_Binary_RejectsOversizedHeaders(t *testing.T) {
+	s := NewSized(10)
+	s.Set(3)
+	data, _ := s.MarshalBinary()
+
+	// bucket counts and lengths which overflow, or which would allocate far
+	// more than the data holds, fail without allocating
+	for _, header := range [][2]uint64{{1 << 62, 1}, {1 << 40, 1}, {1, 1 << 62}, {1, 1 << 40}} {
+		corrupt := append([]byte(nil), data[:binaryHeaderSize]...)
+		binary.LittleEndian.PutUint64(corrupt[16:], header[0])
+		binary.LittleEndian.PutUint64(corrupt[24:], header[1])
+
+		var l Sized
+		AssertTrue(t, l.UnmarshalBinary(corrupt) == ErrInvalidFormat)
+		AssertTrue(t, l.GobDecode(corrupt) == ErrInvalidFormat)
+		_, err := l.ReadFrom(bytes.NewReader(corrupt))
+		AssertTrue(t, err == ErrInvalidFormat)
+	}
+	// a bucket growing by growBy values once full would allocate as much
+	corrupt := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(corrupt[8:], 0xFFFFFFFF)
+	var l Sized
+	AssertTrue(t, l.UnmarshalBinary(corrupt) == ErrInvalidFormat)
+	AssertTrue(t, l.GobDecode(corrupt) == ErrInvalidFormat)
+	_, err := l.ReadFrom(bytes.NewReader(corrupt))
+	AssertTrue(t, err == ErrInvalidFormat)
+
+	binary.LittleEndian.PutUint32(corrupt[8:], maxPrealloc)
+	AssertTrue(t, l.UnmarshalBinary(corrupt) == nil)
+}
+
+// AssertSameLayout checks that two sets have identical buckets
+func AssertSameLayout[T Integer](t *testing.T, actual *SizedOf[T], expected *SizedOf[T]) {
+	t.Helper()
+	AssertEqual(t, actual.Len(), expected.Len())
+	AssertEqual(t, actual.mask, expected.mask)
+	for i, bucket := range expected.buckets {
+		AssertEqual(t, len(actual.buckets[i]), len(bucket))
+		for j, value := range bucket {
+			AssertEqual(t, actual.buckets[i][j], value)
+		}
+	}
+}
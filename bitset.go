@@ -0,0 +1,170 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import "math/bits"
+
+// BitsetOf is a set of values in [0, universe), storing one bit per possible
+// value. When values are dense within a known range, it's far smaller and
+// faster than Sized.
+type BitsetOf[T Integer] struct {
+	words    []uint64
+	universe uint64
+	length   int
+}
+
+// Bitset is an int set of values in [0, universe)
+type Bitset = BitsetOf[int]
+
+// Bitset32 is a uint32 set of values in [0, universe)
+type Bitset32 = BitsetOf[uint32]
+
+// BitsetRune is a rune set of values in [0, universe)
+type BitsetRune = BitsetOf[rune]
+
+// NewBitsetOf creates an empty set which can hold values in [0, universe)
+func NewBitsetOf[T Integer](universe int) *BitsetOf[T] {
+	if universe < 0 {
+		universe = 0
+	}
+	return &BitsetOf[T]{
+		words:    make([]uint64, (universe+63)/64),
+		universe: uint64(universe),
+	}
+}
+
+// NewBitset creates an empty int set which can hold values in [0, universe)
+func NewBitset(universe int) *Bitset {
+	return NewBitsetOf[int](universe)
+}
+
+// NewBitset32 creates an empty uint32 set which can hold values in [0, universe)
+func NewBitset32(universe uint32) *Bitset32 {
+	return NewBitsetOf[uint32](int(universe))
+}
+
+// NewBitsetRune creates an empty rune set which can hold values in [0, universe)
+func NewBitsetRune(universe rune) *BitsetRune {
+	return NewBitsetOf[rune](int(universe))
+}
+
+// Set adds a value to the set. It panics if value isn't in [0, universe).
+func (b *BitsetOf[T]) Set(value T) {
+	if uint64(value) >= b.universe {
+		panic("intset: value outside of bitset universe")
+	}
+	word, bit := uint64(value)>>6, uint64(1)<<(uint64(value)&63)
+	if b.words[word]&bit == 0 {
+		b.words[word] |= bit
+		b.length++
+	}
+}
+
+// Remove returns true if the value existed in the set before being removed
+func (b *BitsetOf[T]) Remove(value T) bool {
+	if uint64(value) >= b.universe {
+		return false
+	}
+	word, bit := uint64(value)>>6, uint64(1)<<(uint64(value)&63)
+	if b.words[word]&bit == 0 {
+		return false
+	}
+	b.words[word] &^= bit
+	b.length--
+	return true
+}
+
+// Exists returns true if the value exists in the set
+func (b *BitsetOf[T]) Exists(value T) bool {
+	if uint64(value) >= b.universe {
+		return false
+	}
+	return b.words[uint64(value)>>6]&(1<<(uint64(value)&63)) != 0
+}
+
+// Len returns the total number of elements in the set
+func (b *BitsetOf[T]) Len() int {
+	return b.length
+}
+
+// Universe returns the upper bound, exclusive, of the values the set can hold
+func (b *BitsetOf[T]) Universe() int {
+	return int(b.universe)
+}
+
+// Each iterates through the set items, in ascending order, and applies
+// function f to each set item
+func (b *BitsetOf[T]) Each(f func(value T)) {
+	for i, word := range b.words {
+		for word != 0 {
+			f(T(i<<6 | bits.TrailingZeros64(word)))
+			word &= word - 1
+		}
+	}
+}
+
+// And returns the values in both b and other. The result's universe is the
+// smaller of theirs.
+func (b *BitsetOf[T]) And(other *BitsetOf[T]) *BitsetOf[T] {
+	small, large := b, other
+	if large.universe < small.universe {
+		small, large = large, small
+	}
+	result := NewBitsetOf[T](int(small.universe))
+	for i := range result.words {
+		result.words[i] = small.words[i] & large.words[i]
+	}
+	result.count()
+	return result
+}
+
+// Or returns the values in either b or other. The result's universe is the
+// larger of theirs.
+func (b *BitsetOf[T]) Or(other *BitsetOf[T]) *BitsetOf[T] {
+	small, large := b, other
+	if large.universe < small.universe {
+		small, large = large, small
+	}
+	result := NewBitsetOf[T](int(large.universe))
+	copy(result.words, large.words)
+	for i, word := range small.words {
+		result.words[i] |= word
+	}
+	result.count()
+	return result
+}
+
+// AndNot returns the values of b which aren't in other. The result has b's
+// universe.
+func (b *BitsetOf[T]) AndNot(other *BitsetOf[T]) *BitsetOf[T] {
+	result := NewBitsetOf[T](int(b.universe))
+	copy(result.words, b.words)
+	for i := 0; i < len(result.words) && i < len(other.words); i++ {
+		result.words[i] &^= other.words[i]
+	}
+	result.count()
+	return result
+}
+
+// Xor returns the values in either b or other, but not both. The result's
+// universe is the larger of theirs.
+func (b *BitsetOf[T]) Xor(other *BitsetOf[T]) *BitsetOf[T] {
+	small, large := b, other
+	if large.universe < small.universe {
+		small, large = large, small
+	}
+	result := NewBitsetOf[T](int(large.universe))
+	copy(result.words, large.words)
+	for i, word := range small.words {
+		result.words[i] ^= word
+	}
+	result.count()
+	return result
+}
+
+// count recomputes the length of the set from its words
+func (b *BitsetOf[T]) count() {
+	b.length = 0
+	for _, word := range b.words {
+		b.length += bits.OnesCount64(word)
+	}
+}
@@ -0,0 +1,77 @@
+package intset
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+var (
+	_ Set     = (*Bitset)(nil)
+	_ Set32   = (*Bitset32)(nil)
+	_ SetRune = (*BitsetRune)(nil)
+)
+
+func Test_Bitset_MatchesOracle(t *testing.T) {
+	AssertOracle[int](t, NewBitset(1000), 1, 1000)
+	AssertOracle[uint32](t, NewBitset32(65), 2, 65)
+	AssertOracle[rune](t, NewBitsetRune(0x10000), 3, 0x10000)
+}
+
+func Test_Bitset_OutsideUniverse(t *testing.T) {
+	b := NewBitsetOf[int8](100)
+	AssertEqual(t, b.Universe(), 100)
+	AssertFalse(t, b.Exists(-1))
+	AssertFalse(t, b.Exists(100))
+	AssertFalse(t, b.Remove(-1))
+	defer func() {
+		AssertTrue(t, recover() != nil)
+		AssertEqual(t, b.Len(), 0)
+	}()
+	b.Set(100)
+}
+
+func Test_Bitset_IteratesInOrder(t *testing.T) {
+	b := NewBitset(200)
+	for _, value := range []int{199, 0, 64, 63, 5} {
+		b.Set(value)
+	}
+	AssertTrue(t, slices.Equal(slices.Collect(b.All()), []int{0, 5, 63, 64, 199}))
+	var values []int
+	b.Each(func(value int) {
+		values = append(values, value)
+	})
+	AssertTrue(t, slices.Equal(values, []int{0, 5, 63, 64, 199}))
+}
+
+func Test_Bitset_Operations(t *testing.T) {
+	r := rand.New(rand.NewSource(22))
+	for round := 0; round < 30; round++ {
+		a, b := NewBitset(1+r.Intn(500)), NewBitset(1+r.Intn(500))
+		for _, set := range []*Bitset{a, b} {
+			for i := 0; i < 200; i++ {
+				set.Set(r.Intn(set.Universe()))
+			}
+		}
+		and, or, andNot, xor := a.And(b), a.Or(b), a.AndNot(b), a.Xor(b)
+		AssertTrue(t, Equal(and, Intersect(a, b)))
+		AssertTrue(t, Equal(or, Union(a, b)))
+		AssertTrue(t, Equal(andNot, Difference(a, b)))
+		AssertTrue(t, Equal(xor, SymmetricDifference(a, b)))
+		AssertEqual(t, and.Universe(), min(a.Universe(), b.Universe()))
+		AssertEqual(t, or.Universe(), max(a.Universe(), b.Universe()))
+		AssertEqual(t, andNot.Universe(), a.Universe())
+		AssertEqual(t, xor.Universe(), max(a.Universe(), b.Universe()))
+	}
+}
+
+func Benchmark_BitsetDenseExists(b *testing.B) {
+	s := NewBitset(1000000)
+	for i := 0; i < 1000000; i++ {
+		s.Set(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Exists(i % 1000000)
+	}
+}
@@ -0,0 +1,117 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"iter"
+	"slices"
+)
+
+// FromSliceOf creates a set holding values, using default configuration.
+// Values are grouped by bucket and each bucket is sorted once, rather than
+// inserted one by one. values isn't modified and may contain duplicates.
+func FromSliceOf[T Integer](values []T) *SizedOf[T] {
+	s := NewSizedOf[T](len(values))
+	grouped, offsets := s.group(values, false)
+	return s.fill(grouped, offsets)
+}
+
+// FromSortedOf creates a set holding values, which should be sorted in
+// ascending order, using default configuration. Buckets are then filled in a
+// single pass; unsorted values fall back to FromSliceOf.
+func FromSortedOf[T Integer](values []T) *SizedOf[T] {
+	if slices.IsSorted(values) == false {
+		return FromSliceOf(values)
+	}
+	s := NewSizedOf[T](len(values))
+	grouped, offsets := s.group(values, true)
+	return s.fill(grouped, offsets)
+}
+
+// CollectOf creates a set holding the values of seq, using default configuration
+func CollectOf[T Integer](seq iter.Seq[T]) *SizedOf[T] {
+	return FromSliceOf(slices.Collect(seq))
+}
+
+// SetMany adds values to the set. Values are grouped by bucket and sorted
+// once, and each bucket they're added to is rewritten with exactly enough
+// capacity, rather than values being inserted one by one.
+func (s *SizedOf[T]) SetMany(values []T) {
+	grouped, offsets := s.group(values, false)
+	total := 0
+	for i, bucket := range s.buckets {
+		if n := offsets[i+1] - offsets[i]; n > 0 {
+			total += len(bucket) + n
+		}
+	}
+	// every bucket values are added to shares this backing array
+	merged := make([]T, 0, total)
+	for i, bucket := range s.buckets {
+		group := grouped[offsets[i]:offsets[i+1]]
+		if len(group) == 0 {
+			continue
+		}
+		start := len(merged)
+		merged = appendUnion(merged, bucket, group)
+		s.buckets[i] = merged[start:len(merged):len(merged)]
+		s.length += len(merged) - start - len(bucket)
+		if s.shared != nil {
+			s.shared[i] = false
+		}
+	}
+	s.fit()
+}
+
+// group returns a copy of values arranged by bucket, each sorted and without
+// duplicates, and offsets such that bucket i's values are
+// grouped[offsets[i]:offsets[i+1]]. When values are sorted, so are the values
+// of each bucket, since they're copied in order.
+func (s *SizedOf[T]) group(values []T, sorted bool) ([]T, []int) {
+	offsets := make([]int, len(s.buckets)+1)
+	for _, value := range values {
+		offsets[s.bucket(value)+1]++
+	}
+	for i := 1; i < len(offsets); i++ {
+		offsets[i] += offsets[i-1]
+	}
+	grouped := make([]T, len(values))
+	next := append([]int(nil), offsets[:len(s.buckets)]...)
+	for _, value := range values {
+		index := s.bucket(value)
+		grouped[next[index]] = value
+		next[index]++
+	}
+
+	// sort and deduplicate each bucket, compacting them as we go
+	n := 0
+	for i := range s.buckets {
+		bucket := grouped[offsets[i]:offsets[i+1]]
+		if sorted == false {
+			slices.Sort(bucket)
+		}
+		offsets[i] = n
+		for j, value := range bucket {
+			if j == 0 || value != grouped[n-1] {
+				grouped[n] = value
+				n++
+			}
+		}
+	}
+	offsets[len(s.buckets)] = n
+	return grouped[:n], offsets
+}
+
+// FromSlice creates an int set holding values, using default configuration
+func FromSlice(values []int) *Sized {
+	return FromSliceOf(values)
+}
+
+// FromSorted creates an int set holding values, which should be sorted in
+// ascending order, using default configuration
+func FromSorted(values []int) *Sized {
+	return FromSortedOf(values)
+}
+
+// Collect creates an int set holding the values of seq, using default configuration
+func Collect(seq iter.Seq[int]) *Sized {
+	return CollectOf(seq)
+}
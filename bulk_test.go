@@ -0,0 +1,111 @@
+package intset
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_Bulk_FromSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(25))
+	values := make([]int, 5000)
+	for i := range values {
+		values[i] = r.Intn(4000) - 2000
+	}
+	original := slices.Clone(values)
+	s := FromSlice(values)
+	AssertTrue(t, slices.Equal(values, original))
+	AssertSorted(t, s)
+	AssertExact(t, s)
+	AssertTrue(t, Equal(s, oracleOf(values)))
+
+	slices.Sort(values)
+	AssertTrue(t, Equal(FromSorted(values), s))
+	AssertTrue(t, Equal(Collect(slices.Values(values)), s))
+	AssertEqual(t, FromSlice(nil).Len(), 0)
+}
+
+func Test_Bulk_FromSortedFallsBack(t *testing.T) {
+	s := FromSorted32([]uint32{9, 1, 5, 1})
+	AssertSorted(t, s)
+	AssertEqual(t, s.Len(), 3)
+	AssertTrue(t, s.Exists(1))
+
+	r := FromSortedRune([]rune{'a', 'a', 'b', 'z'})
+	AssertSorted(t, r)
+	AssertEqual(t, r.Len(), 3)
+	AssertEqual(t, FromSliceRune([]rune("hello")).Len(), 4)
+	AssertEqual(t, CollectRune(slices.Values([]rune("hello"))).Len(), 4)
+	AssertEqual(t, FromSlice32([]uint32{3, 3}).Len(), 1)
+	AssertEqual(t, Collect32(slices.Values([]uint32{3, 4})).Len(), 2)
+}
+
+func Test_Bulk_SetMany(t *testing.T) {
+	r := rand.New(rand.NewSource(26))
+	configs := []*Config{NewConfig(), NewConfig().Hasher(FibonacciHash), NewConfig().AutoGrow(4)}
+	for _, config := range configs {
+		s := NewSizedConfig(64, config)
+		var all []int
+		for round := 0; round < 10; round++ {
+			values := make([]int, r.Intn(300))
+			for i := range values {
+				values[i] = r.Intn(2000)
+			}
+			s.SetMany(values)
+			all = append(all, values...)
+			AssertSorted(t, s)
+			AssertTrue(t, Equal(s, oracleOf(all)))
+			value := r.Intn(2000)
+			s.Set(value)
+			all = append(all, value)
+		}
+	}
+}
+
+func Test_Bulk_SetManyDoesNotModifySharedBuckets(t *testing.T) {
+	s := FromSlice([]int{1, 2, 3})
+	snapshot := s.clone()
+	s.SetMany([]int{4, 5, 6, 1})
+	AssertEqual(t, s.Len(), 6)
+	AssertEqual(t, snapshot.Len(), 3)
+	AssertFalse(t, snapshot.Exists(4))
+	AssertSorted(t, snapshot)
+	s.Set(7)
+	AssertFalse(t, snapshot.Exists(7))
+}
+
+// AssertExact checks that every bucket has exactly enough capacity
+func AssertExact[T Integer](t *testing.T, s *SizedOf[T]) {
+	t.Helper()
+	for _, bucket := range s.buckets {
+		AssertEqual(t, cap(bucket), len(bucket))
+	}
+}
+
+// oracleOf returns a set of values built one value at a time
+func oracleOf(values []int) *Sized {
+	s := NewSized(len(values))
+	for _, value := range values {
+		s.Set(value)
+	}
+	return s
+}
+
+func Benchmark_BulkFromSlice(b *testing.B) {
+	values := rand.Perm(1000000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FromSlice(values)
+	}
+}
+
+func Benchmark_BulkSetEach(b *testing.B) {
+	values := rand.Perm(1000000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := NewSized(len(values))
+		for _, value := range values {
+			s.Set(value)
+		}
+	}
+}
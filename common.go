@@ -1,6 +1,14 @@
 // Package intset provides a specialized set for integers or runes
 package intset
 
+import "unsafe"
+
+// Integer is the set of element types a SizedOf can hold
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
 // http://graphics.stanford.edu/~seander/bithacks.html#RoundUpPowerOf2
 func upTwo(v int) int {
 	v--
@@ -12,3 +20,24 @@ func upTwo(v int) int {
 	v++
 	return v
 }
+
+// bitsOf returns the width of T in bits
+func bitsOf[T Integer]() int {
+	var zero T
+	return int(unsafe.Sizeof(zero)) * 8
+}
+
+// search returns the index of the first value of sorted values which is at
+// least value
+func search[T Integer](values []T, value T) int {
+	i, j := 0, len(values)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if values[h] < value {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
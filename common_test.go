@@ -1,14 +1,64 @@
 package intset
 
-import "testing"
+import (
+	"math/rand"
+	"testing"
+)
 
 func Test_ConfigZeroBucketSize(t *testing.T) {
 	config := NewConfig().BucketSize(0)
 	AssertTrue(t, config.bucketSize == defaultBucketSize)
 }
 
+func Test_ConfigAutoResize(t *testing.T) {
+	config := NewConfig().AutoGrow(4).AutoShrink(0.5)
+	AssertEqual(t, config.growLoad, 4.0)
+	AssertEqual(t, config.shrinkLoad, 0.5)
+	AssertEqual(t, Default.growLoad, 0.0)
+}
+
 // Common testing utility functions
 
+// mutableSet is a set which AssertOracle can modify
+type mutableSet[T Integer] interface {
+	SetOf[T]
+	Set(value T)
+	Remove(value T) bool
+}
+
+// AssertOracle interleaves random Set, Remove and Exists calls against s and
+// a map, failing as soon as the two disagree
+func AssertOracle[T Integer](t *testing.T, s mutableSet[T], seed int64, span int) {
+	t.Helper()
+	r := rand.New(rand.NewSource(seed))
+	oracle := make(map[T]struct{})
+	for i := 0; i < 20000; i++ {
+		value := T(r.Intn(span))
+		_, exists := oracle[value]
+		switch r.Intn(3) {
+		case 0:
+			s.Set(value)
+			oracle[value] = struct{}{}
+		case 1:
+			AssertEqual(t, s.Remove(value), exists)
+			delete(oracle, value)
+		default:
+			AssertEqual(t, s.Exists(value), exists)
+		}
+		AssertEqual(t, s.Len(), len(oracle))
+	}
+	for value := range oracle {
+		AssertTrue(t, s.Exists(value))
+	}
+	count := 0
+	s.Each(func(value T) {
+		_, exists := oracle[value]
+		AssertTrue(t, exists)
+		count++
+	})
+	AssertEqual(t, count, len(oracle))
+}
+
 // AssertEqual checks if two values are equal
 func AssertEqual[T comparable](t *testing.T, actual T, expected T) {
 	t.Helper()
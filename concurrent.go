@@ -0,0 +1,142 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentOf is a set which is safe for concurrent use. Its buckets are
+// partitioned into stripes, each guarded by its own lock, so that writers to
+// different stripes don't contend. AutoGrow and AutoShrink are ignored.
+type ConcurrentOf[T Integer] struct {
+	length  int64
+	set     *SizedOf[T]
+	mask    uint64
+	stripes []sync.RWMutex
+}
+
+// Concurrent is an int set which is safe for concurrent use
+type Concurrent = ConcurrentOf[int]
+
+// Concurrent32 is a uint32 set which is safe for concurrent use
+type Concurrent32 = ConcurrentOf[uint32]
+
+// ConcurrentRune is a rune set which is safe for concurrent use
+type ConcurrentRune = ConcurrentOf[rune]
+
+// NewConcurrentOf creates an empty concurrent set with target capacity specified by size using default configuration
+func NewConcurrentOf[T Integer](size int) *ConcurrentOf[T] {
+	return NewConcurrentOfConfig[T](size, Default)
+}
+
+// NewConcurrentOfConfig creates an empty concurrent set with target capacity specified by size
+func NewConcurrentOfConfig[T Integer](size int, config *Config) *ConcurrentOf[T] {
+	set := NewSizedOfConfig[T](size, config)
+	stripes := config.stripes
+	if stripes == 0 {
+		stripes = defaultStripes
+	}
+	if stripes > len(set.buckets) {
+		stripes = len(set.buckets)
+	}
+	return &ConcurrentOf[T]{
+		set:     set,
+		mask:    uint64(stripes) - 1,
+		stripes: make([]sync.RWMutex, stripes),
+	}
+}
+
+// NewConcurrent creates an empty concurrent int set with target capacity specified by size using default configuration
+func NewConcurrent(size int) *Concurrent {
+	return NewConcurrentOf[int](size)
+}
+
+// NewConcurrentConfig creates an empty concurrent int set with target capacity specified by size
+func NewConcurrentConfig(size int, config *Config) *Concurrent {
+	return NewConcurrentOfConfig[int](size, config)
+}
+
+// NewConcurrent32 creates an empty concurrent uint32 set with target capacity specified by size using default configuration
+func NewConcurrent32(size uint32) *Concurrent32 {
+	return NewConcurrentOf[uint32](int(size))
+}
+
+// NewConcurrent32Config creates an empty concurrent uint32 set with target capacity specified by size
+func NewConcurrent32Config(size uint32, config *Config) *Concurrent32 {
+	return NewConcurrentOfConfig[uint32](int(size), config)
+}
+
+// NewConcurrentRune creates an empty concurrent rune set with target capacity specified by size using default configuration
+func NewConcurrentRune(size rune) *ConcurrentRune {
+	return NewConcurrentOf[rune](int(size))
+}
+
+// NewConcurrentRuneConfig creates an empty concurrent rune set with target capacity specified by size
+func NewConcurrentRuneConfig(size rune, config *Config) *ConcurrentRune {
+	return NewConcurrentOfConfig[rune](int(size), config)
+}
+
+// Set adds a value to the set
+func (c *ConcurrentOf[T]) Set(value T) {
+	index := c.set.bucket(value)
+	stripe := &c.stripes[index&c.mask]
+	stripe.Lock()
+	added := c.set.insert(index, value)
+	stripe.Unlock()
+	if added {
+		atomic.AddInt64(&c.length, 1)
+	}
+}
+
+// Remove returns true if the value existed in the set before being removed
+func (c *ConcurrentOf[T]) Remove(value T) bool {
+	index := c.set.bucket(value)
+	stripe := &c.stripes[index&c.mask]
+	stripe.Lock()
+	removed := c.set.delete(index, value)
+	stripe.Unlock()
+	if removed {
+		atomic.AddInt64(&c.length, -1)
+	}
+	return removed
+}
+
+// Exists returns true if the value exists in the set
+func (c *ConcurrentOf[T]) Exists(value T) bool {
+	index := c.set.bucket(value)
+	stripe := &c.stripes[index&c.mask]
+	stripe.RLock()
+	exists := c.set.exists(value, c.set.buckets[index])
+	stripe.RUnlock()
+	return exists
+}
+
+// Len returns the total number of elements in the set
+func (c *ConcurrentOf[T]) Len() int {
+	return int(atomic.LoadInt64(&c.length))
+}
+
+// Each iterates through a consistent snapshot of the set items and applies
+// function f to each set item. f is called without holding any lock, so it
+// may safely modify the set.
+func (c *ConcurrentOf[T]) Each(f func(value T)) {
+	for _, value := range c.snapshot() {
+		f(value)
+	}
+}
+
+// snapshot copies every value while holding all the stripe locks
+func (c *ConcurrentOf[T]) snapshot() []T {
+	for i := range c.stripes {
+		c.stripes[i].RLock()
+	}
+	values := make([]T, 0, c.Len())
+	for _, bucket := range c.set.buckets {
+		values = append(values, bucket...)
+	}
+	for i := range c.stripes {
+		c.stripes[i].RUnlock()
+	}
+	return values
+}
@@ -0,0 +1,103 @@
+package intset
+
+import (
+	"sync"
+	"testing"
+)
+
+var (
+	_ Set     = (*Concurrent)(nil)
+	_ Set32   = (*Concurrent32)(nil)
+	_ SetRune = (*ConcurrentRune)(nil)
+)
+
+func Test_Concurrent_SetsAndRemoves(t *testing.T) {
+	s := NewConcurrent(100)
+	for i := 0; i < 100; i++ {
+		s.Set(i)
+		s.Set(i)
+	}
+	AssertEqual(t, s.Len(), 100)
+	AssertTrue(t, s.Remove(5))
+	AssertFalse(t, s.Remove(5))
+	AssertFalse(t, s.Exists(5))
+	AssertTrue(t, s.Exists(6))
+	AssertEqual(t, s.Len(), 99)
+}
+
+func Test_Concurrent_StripesAreCappedByBuckets(t *testing.T) {
+	AssertEqual(t, len(NewConcurrent32(4).stripes), 1)
+	AssertEqual(t, len(NewConcurrentRune(10000).stripes), defaultStripes)
+	AssertEqual(t, len(NewConcurrentConfig(10000, NewConfig().Stripes(5)).stripes), 8)
+	AssertEqual(t, len(NewConcurrentConfig(10000, NewConfig().Stripes(0)).stripes), defaultStripes)
+}
+
+func Test_Concurrent_ParallelWritersAndReaders(t *testing.T) {
+	s := NewConcurrentConfig(1000, NewConfig().Hasher(FibonacciHash))
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				value := i*8 + w
+				s.Set(value)
+				if i%3 == 0 {
+					s.Remove(value)
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				s.Exists(i)
+				if i%500 == 0 {
+					s.Each(func(value int) {
+						s.Exists(value)
+					})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	AssertEqual(t, s.Len(), 8*(2000-667))
+	for i := 0; i < 16000; i++ {
+		AssertEqual(t, s.Exists(i), (i/8)%3 != 0)
+	}
+}
+
+func Test_Concurrent_EachIsASnapshot(t *testing.T) {
+	s := NewConcurrentRune(10)
+	for i := rune(0); i < 10; i++ {
+		s.Set(i)
+	}
+	count := 0
+	s.Each(func(value rune) {
+		s.Remove(value)
+		s.Set(value + 100)
+		count++
+	})
+	AssertEqual(t, count, 10)
+	AssertEqual(t, s.Len(), 10)
+	AssertTrue(t, s.Exists(100))
+	AssertFalse(t, s.Exists(0))
+}
+
+func Benchmark_ConcurrentParallelExists(b *testing.B) {
+	s := NewConcurrent(1000000)
+	for i := 0; i < 1000000; i++ {
+		s.Set(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s.Exists(i % 1000000)
+			i++
+		}
+	})
+}
@@ -1,9 +1,12 @@
 // Package intset provides a specialized set for integers or runes
 package intset
 
+import "reflect"
+
 const (
 	defaultBucketSize   int = 4
 	defaultBucketGrowBy int = 1
+	defaultStripes      int = 32
 )
 
 // Config defines the configuration for creating a new set
@@ -11,14 +14,21 @@ const (
 // Smaller values for bucketSize will speed up lookups but
 // also increase memory usage. Smaller values for bucketGrowBy will slow down
 // the set capacity growth rate but also slow down insertions.
+//
+// By default a set never changes its number of buckets. AutoGrow and AutoShrink
+// let it rehash itself as the number of elements drifts from the original size.
 type Config struct {
 	bucketSize   int
 	bucketGrowBy int
+	growLoad     float64
+	shrinkLoad   float64
+	hash         func(uint64) uint64
+	stripes      int
 }
 
 // NewConfig creates a new config with usable defaults
 func NewConfig() *Config {
-	return &Config{bucketSize: defaultBucketSize, bucketGrowBy: defaultBucketGrowBy}
+	return &Config{bucketSize: defaultBucketSize, bucketGrowBy: defaultBucketGrowBy, stripes: defaultStripes}
 }
 
 // BucketSize sets the initial bucket size
@@ -37,6 +47,47 @@ func (c *Config) BucketGrowBy(growBy uint32) *Config {
 	return c
 }
 
+// AutoGrow doubles the number of buckets whenever the average bucket length
+// exceeds loadFactor. 0, the default, disables growing.
+func (c *Config) AutoGrow(loadFactor float64) *Config {
+	c.growLoad = loadFactor
+	return c
+}
+
+// AutoShrink halves the number of buckets whenever the average bucket length
+// falls below loadFactor. 0, the default, disables shrinking. To avoid
+// thrashing, loadFactor should be well below half of the AutoGrow load factor.
+func (c *Config) AutoShrink(loadFactor float64) *Config {
+	c.shrinkLoad = loadFactor
+	return c
+}
+
+// Hasher sets the function used to scatter values across buckets. The
+// default, nil or IdentityHash, uses the value itself, which only works well
+// when values are naturally random. See FibonacciHash, Murmur3Hash and XXHash
+// for sequential or otherwise structured values.
+func (c *Config) Hasher(hash func(uint64) uint64) *Config {
+	// IdentityHash is stored as nil so that sets using it are laid out, encoded
+	// and combined exactly like sets using the default
+	if hash != nil && reflect.ValueOf(hash).Pointer() == reflect.ValueOf(IdentityHash).Pointer() {
+		hash = nil
+	}
+	c.hash = hash
+	return c
+}
+
+// Stripes sets the number of locks a concurrent set partitions its buckets
+// into, rounded up to a power of two. More stripes reduce contention between
+// writers at the cost of a slower Each.
+func (c *Config) Stripes(count uint32) *Config {
+	if count == 0 { // stripes must be positive int
+		c.stripes = defaultStripes
+	} else {
+		c.stripes = upTwo(int(count))
+	}
+	return c
+}
+
 // Default is a default Config which favors probing performance
 // at the cost of memory.
 var Default = NewConfig()
@@ -0,0 +1,519 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import "math/bits"
+
+const (
+	arrayContainer = iota
+	bitmapContainer
+	runContainer
+)
+
+const (
+	// arrayMax is the most values an array container holds; beyond it, a
+	// bitmap is smaller
+	arrayMax = 4096
+	// runMax is the most runs a run container holds; beyond it, a bitmap is
+	// smaller
+	runMax = 2048
+	// bitmapWords is the number of words of a bitmap container
+	bitmapWords = 1 << 16 / 64
+)
+
+// interval is a run of consecutive values, from start to last inclusively
+type interval struct {
+	start, last uint16
+}
+
+// container holds the low 16 bits of the values of a RoaringOf sharing the
+// same high bits, as a sorted array, a bitmap or sorted runs, depending on kind
+type container struct {
+	kind   uint8
+	length int
+	array  []uint16
+	bitmap []uint64
+	runs   []interval
+}
+
+// contains returns true if low is in the container
+func (c *container) contains(low uint16) bool {
+	switch c.kind {
+	case arrayContainer:
+		i := search(c.array, low)
+		return i < len(c.array) && c.array[i] == low
+	case bitmapContainer:
+		return c.bitmap[low>>6]&(1<<(low&63)) != 0
+	}
+	i := searchRuns(c.runs, low)
+	return i < len(c.runs) && c.runs[i].start <= low
+}
+
+// add returns true if low wasn't in the container before being added
+func (c *container) add(low uint16) bool {
+	switch c.kind {
+	case arrayContainer:
+		i := search(c.array, low)
+		if i < len(c.array) && c.array[i] == low {
+			return false
+		}
+		if len(c.array) == arrayMax {
+			c.toBitmap()
+			return c.add(low)
+		}
+		c.array = append(c.array, 0)
+		copy(c.array[i+1:], c.array[i:])
+		c.array[i] = low
+	case bitmapContainer:
+		word, bit := low>>6, uint64(1)<<(low&63)
+		if c.bitmap[word]&bit != 0 {
+			return false
+		}
+		c.bitmap[word] |= bit
+	default:
+		if c.addRun(low) == false {
+			return false
+		}
+	}
+	c.length++
+	return true
+}
+
+// addRun adds low to a run container, extending or joining the runs around it
+func (c *container) addRun(low uint16) bool {
+	i := searchRuns(c.runs, low)
+	if i < len(c.runs) && c.runs[i].start <= low {
+		return false
+	}
+	before := i > 0 && c.runs[i-1].last+1 == low
+	after := i < len(c.runs) && c.runs[i].start-1 == low
+	switch {
+	case before && after:
+		c.runs[i-1].last = c.runs[i].last
+		c.runs = append(c.runs[:i], c.runs[i+1:]...)
+	case before:
+		c.runs[i-1].last = low
+	case after:
+		c.runs[i].start = low
+	default:
+		c.runs = append(c.runs, interval{})
+		copy(c.runs[i+1:], c.runs[i:])
+		c.runs[i] = interval{low, low}
+		if len(c.runs) > runMax {
+			c.toBitmap()
+		}
+	}
+	return true
+}
+
+// remove returns true if low was in the container before being removed
+func (c *container) remove(low uint16) bool {
+	switch c.kind {
+	case arrayContainer:
+		i := search(c.array, low)
+		if i == len(c.array) || c.array[i] != low {
+			return false
+		}
+		c.array = append(c.array[:i], c.array[i+1:]...)
+	case bitmapContainer:
+		word, bit := low>>6, uint64(1)<<(low&63)
+		if c.bitmap[word]&bit == 0 {
+			return false
+		}
+		c.bitmap[word] &^= bit
+	default:
+		if c.removeRun(low) == false {
+			return false
+		}
+	}
+	c.length--
+	if c.kind == bitmapContainer && c.length <= arrayMax {
+		c.toArray()
+	}
+	return true
+}
+
+// removeRun removes low from a run container, shrinking or splitting its run
+func (c *container) removeRun(low uint16) bool {
+	i := searchRuns(c.runs, low)
+	if i == len(c.runs) || c.runs[i].start > low {
+		return false
+	}
+	run := c.runs[i]
+	switch {
+	case run.start == run.last:
+		c.runs = append(c.runs[:i], c.runs[i+1:]...)
+	case low == run.start:
+		c.runs[i].start++
+	case low == run.last:
+		c.runs[i].last--
+	default:
+		c.runs[i].last = low - 1
+		c.runs = append(c.runs, interval{})
+		copy(c.runs[i+2:], c.runs[i+1:])
+		c.runs[i+1] = interval{low + 1, run.last}
+		if len(c.runs) > runMax {
+			c.toBitmap()
+		}
+	}
+	return true
+}
+
+// each calls yield for every value of the container in ascending order,
+// returning false if yield did
+func (c *container) each(yield func(low uint16) bool) bool {
+	switch c.kind {
+	case arrayContainer:
+		for _, low := range c.array {
+			if yield(low) == false {
+				return false
+			}
+		}
+	case bitmapContainer:
+		for i, word := range c.bitmap {
+			for word != 0 {
+				if yield(uint16(i<<6|bits.TrailingZeros64(word))) == false {
+					return false
+				}
+				word &= word - 1
+			}
+		}
+	default:
+		for _, run := range c.runs {
+			for low := run.start; ; low++ {
+				if yield(low) == false {
+					return false
+				}
+				if low == run.last {
+					break
+				}
+			}
+		}
+	}
+	return true
+}
+
+// orInto sets the bits of the container's values in bitmap
+func (c *container) orInto(bitmap []uint64) {
+	switch c.kind {
+	case arrayContainer:
+		for _, low := range c.array {
+			bitmap[low>>6] |= 1 << (low & 63)
+		}
+	case bitmapContainer:
+		for i, word := range c.bitmap {
+			bitmap[i] |= word
+		}
+	default:
+		for _, run := range c.runs {
+			setRange(bitmap, run.start, run.last)
+		}
+	}
+}
+
+// words returns the container as a bitmap, which mustn't be modified
+func (c *container) words() []uint64 {
+	if c.kind == bitmapContainer {
+		return c.bitmap
+	}
+	bitmap := make([]uint64, bitmapWords)
+	c.orInto(bitmap)
+	return bitmap
+}
+
+// intervals returns the runs of consecutive values in the container
+func (c *container) intervals() []interval {
+	if c.kind == runContainer {
+		return c.runs
+	}
+	var runs []interval
+	c.each(func(low uint16) bool {
+		if n := len(runs); n > 0 && runs[n-1].last+1 == low {
+			runs[n-1].last = low
+		} else {
+			runs = append(runs, interval{low, low})
+		}
+		return true
+	})
+	return runs
+}
+
+// countRuns returns the number of runs of consecutive values in the container
+func (c *container) countRuns() int {
+	switch c.kind {
+	case arrayContainer:
+		count := 0
+		for i, low := range c.array {
+			if i == 0 || c.array[i-1]+1 != low {
+				count++
+			}
+		}
+		return count
+	case bitmapContainer:
+		// a run starts wherever a bit is set and the one before it isn't
+		count := 0
+		var carry uint64
+		for _, word := range c.bitmap {
+			count += bits.OnesCount64(word &^ (word<<1 | carry))
+			carry = word >> 63
+		}
+		return count
+	}
+	return len(c.runs)
+}
+
+// optimize converts the container to whichever of an array, a bitmap or runs
+// takes the least memory
+func (c *container) optimize() {
+	runs := c.countRuns()
+	switch {
+	case runs*4 < c.length*2 && runs*4 < bitmapWords*8:
+		if c.kind != runContainer {
+			*c = container{kind: runContainer, length: c.length, runs: c.intervals()}
+		}
+	case c.length <= arrayMax:
+		c.toArray()
+	default:
+		c.toBitmap()
+	}
+}
+
+// toBitmap converts the container to a bitmap
+func (c *container) toBitmap() {
+	if c.kind == bitmapContainer {
+		return
+	}
+	bitmap := make([]uint64, bitmapWords)
+	c.orInto(bitmap)
+	*c = container{kind: bitmapContainer, length: c.length, bitmap: bitmap}
+}
+
+// toArray converts the container to an array
+func (c *container) toArray() {
+	if c.kind == arrayContainer {
+		return
+	}
+	array := make([]uint16, 0, c.length)
+	c.each(func(low uint16) bool {
+		array = append(array, low)
+		return true
+	})
+	*c = container{kind: arrayContainer, length: c.length, array: array}
+}
+
+// clone returns a copy of the container which doesn't share its memory
+func (c *container) clone() container {
+	clone := *c
+	switch c.kind {
+	case arrayContainer:
+		clone.array = append([]uint16(nil), c.array...)
+	case bitmapContainer:
+		clone.bitmap = append([]uint64(nil), c.bitmap...)
+	default:
+		clone.runs = append([]interval(nil), c.runs...)
+	}
+	return clone
+}
+
+// andContainers returns the values in both a and b
+func andContainers(a, b *container) container {
+	if b.kind == arrayContainer {
+		a, b = b, a
+	}
+	switch {
+	case a.kind == arrayContainer && b.kind == arrayContainer:
+		array := keepIntersection(append([]uint16(nil), a.array...), b.array)
+		return arrayOf(array)
+	case a.kind == arrayContainer:
+		array := make([]uint16, 0, len(a.array))
+		for _, low := range a.array {
+			if b.contains(low) {
+				array = append(array, low)
+			}
+		}
+		return arrayOf(array)
+	case a.kind == runContainer && b.kind == runContainer:
+		return runsOf(andRuns(a.runs, b.runs))
+	}
+	bitmap := append([]uint64(nil), a.words()...)
+	for i, word := range b.words() {
+		bitmap[i] &= word
+	}
+	return bitmapOf(bitmap)
+}
+
+// orContainers returns the values in either a or b
+func orContainers(a, b *container) container {
+	switch {
+	case a.kind == arrayContainer && b.kind == arrayContainer && len(a.array)+len(b.array) <= arrayMax:
+		return arrayOf(appendUnion(make([]uint16, 0, len(a.array)+len(b.array)), a.array, b.array))
+	case a.kind == runContainer && b.kind == runContainer:
+		if runs := orRuns(a.runs, b.runs); len(runs) <= runMax {
+			return runsOf(runs)
+		}
+	}
+	bitmap := make([]uint64, bitmapWords)
+	a.orInto(bitmap)
+	b.orInto(bitmap)
+	return bitmapOf(bitmap)
+}
+
+// andNotContainers returns the values of a which aren't in b
+func andNotContainers(a, b *container) container {
+	switch {
+	case a.kind == arrayContainer && b.kind == arrayContainer:
+		return arrayOf(appendDifference(make([]uint16, 0, len(a.array)), a.array, b.array))
+	case a.kind == arrayContainer:
+		array := make([]uint16, 0, len(a.array))
+		for _, low := range a.array {
+			if b.contains(low) == false {
+				array = append(array, low)
+			}
+		}
+		return arrayOf(array)
+	case a.kind == runContainer && b.kind == runContainer:
+		if runs := andNotRuns(a.runs, b.runs); len(runs) <= runMax {
+			return runsOf(runs)
+		}
+	}
+	bitmap := append([]uint64(nil), a.words()...)
+	for i, word := range b.words() {
+		bitmap[i] &^= word
+	}
+	return bitmapOf(bitmap)
+}
+
+// xorContainers returns the values in either a or b, but not both
+func xorContainers(a, b *container) container {
+	if a.kind == arrayContainer && b.kind == arrayContainer {
+		array := appendSymmetricDifference(make([]uint16, 0, len(a.array)+len(b.array)), a.array, b.array)
+		if len(array) <= arrayMax {
+			return arrayOf(array)
+		}
+	}
+	bitmap := append([]uint64(nil), a.words()...)
+	for i, word := range b.words() {
+		bitmap[i] ^= word
+	}
+	return bitmapOf(bitmap)
+}
+
+// arrayOf returns an array container holding sorted array
+func arrayOf(array []uint16) container {
+	return container{kind: arrayContainer, length: len(array), array: array}
+}
+
+// runsOf returns a run container holding sorted runs
+func runsOf(runs []interval) container {
+	length := 0
+	for _, run := range runs {
+		length += int(run.last-run.start) + 1
+	}
+	return container{kind: runContainer, length: length, runs: runs}
+}
+
+// bitmapOf returns a container holding the values of bitmap, as an array if
+// there are few enough of them
+func bitmapOf(bitmap []uint64) container {
+	length := 0
+	for _, word := range bitmap {
+		length += bits.OnesCount64(word)
+	}
+	c := container{kind: bitmapContainer, length: length, bitmap: bitmap}
+	if length <= arrayMax {
+		c.toArray()
+	}
+	return c
+}
+
+// andRuns returns the intersection of sorted runs a and b
+func andRuns(a, b []interval) []interval {
+	var runs []interval
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		start, last := max(a[i].start, b[j].start), min(a[i].last, b[j].last)
+		if start <= last {
+			runs = append(runs, interval{start, last})
+		}
+		if a[i].last < b[j].last {
+			i++
+		} else {
+			j++
+		}
+	}
+	return runs
+}
+
+// orRuns returns the union of sorted runs a and b, joining touching runs
+func orRuns(a, b []interval) []interval {
+	runs := make([]interval, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var next interval
+		if j == len(b) || (i < len(a) && a[i].start < b[j].start) {
+			next = a[i]
+			i++
+		} else {
+			next = b[j]
+			j++
+		}
+		if n := len(runs); n > 0 && int(next.start) <= int(runs[n-1].last)+1 {
+			runs[n-1].last = max(runs[n-1].last, next.last)
+		} else {
+			runs = append(runs, next)
+		}
+	}
+	return runs
+}
+
+// andNotRuns returns the values of sorted runs a which aren't in sorted runs b
+func andNotRuns(a, b []interval) []interval {
+	var runs []interval
+	j := 0
+	for _, run := range a {
+		start := int(run.start)
+		for j < len(b) && int(b[j].last) < start {
+			j++
+		}
+		for k := j; k < len(b) && b[k].start <= run.last; k++ {
+			if int(b[k].start) > start {
+				runs = append(runs, interval{uint16(start), b[k].start - 1})
+			}
+			start = int(b[k].last) + 1
+		}
+		if start <= int(run.last) {
+			runs = append(runs, interval{uint16(start), run.last})
+		}
+	}
+	return runs
+}
+
+// setRange sets the bits from start to last, inclusively, in bitmap
+func setRange(bitmap []uint64, start, last uint16) {
+	first, end := int(start>>6), int(last>>6)
+	low := ^uint64(0) << (start & 63)
+	high := ^uint64(0) >> (63 - last&63)
+	if first == end {
+		bitmap[first] |= low & high
+		return
+	}
+	bitmap[first] |= low
+	for i := first + 1; i < end; i++ {
+		bitmap[i] = ^uint64(0)
+	}
+	bitmap[end] |= high
+}
+
+// searchRuns returns the index of the first of sorted runs which ends at or
+// after low
+func searchRuns(runs []interval, low uint16) int {
+	i, j := 0, len(runs)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if runs[h].last < low {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
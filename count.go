@@ -0,0 +1,260 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+// IntersectCountOf returns the number of values in every set. It doesn't
+// allocate when the smallest set is a SizedOf.
+func IntersectCountOf[T Integer](sets ...SetOf[T]) int {
+	switch len(sets) {
+	case 0:
+		return 0
+	case 2:
+		return intersectCount(sets[0], sets[1])
+	}
+	driver := 0
+	for i, set := range sets {
+		if set.Len() < sets[driver].Len() {
+			driver = i
+		}
+	}
+	if s, ok := sets[driver].(*SizedOf[T]); ok {
+		count := 0
+		for _, bucket := range s.buckets {
+			for _, value := range bucket {
+				if inAll(sets, driver, value) {
+					count++
+				}
+			}
+		}
+		return count
+	}
+	return countInAll(sets, driver)
+}
+
+// UnionCountOf returns the number of values in at least one set. It doesn't
+// allocate when the sets are SizedOf.
+func UnionCountOf[T Integer](sets ...SetOf[T]) int {
+	if len(sets) == 2 {
+		return sets[0].Len() + sets[1].Len() - intersectCount(sets[0], sets[1])
+	}
+	// count each value in the first set which has it
+	count := 0
+	for i, set := range sets {
+		if i == 0 {
+			count += set.Len()
+			continue
+		}
+		s, ok := set.(*SizedOf[T])
+		if ok == false {
+			count += countNotInAny(set, sets[:i])
+			continue
+		}
+		for _, bucket := range s.buckets {
+			for _, value := range bucket {
+				if inAny(sets[:i], value) == false {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// DifferenceCountOf returns the number of values of a which aren't in b
+func DifferenceCountOf[T Integer](a, b SetOf[T]) int {
+	return a.Len() - intersectCount(a, b)
+}
+
+// JaccardOf returns the Jaccard index of a and b, |a ∩ b| / |a ∪ b|, or 0
+// when both are empty
+func JaccardOf[T Integer](a, b SetOf[T]) float64 {
+	intersection := intersectCount(a, b)
+	union := a.Len() + b.Len() - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// OverlapOf returns the overlap coefficient of a and b,
+// |a ∩ b| / min(|a|, |b|), or 0 when either is empty
+func OverlapOf[T Integer](a, b SetOf[T]) float64 {
+	smallest := a.Len()
+	if l := b.Len(); l < smallest {
+		smallest = l
+	}
+	if smallest == 0 {
+		return 0
+	}
+	return float64(intersectCount(a, b)) / float64(smallest)
+}
+
+// AtLeastOf returns the values which are in at least k of the sets. k <= 1
+// is the union of the sets, and k == len(sets) their intersection.
+func AtLeastOf[T Integer](k int, sets ...SetOf[T]) *SizedOf[T] {
+	switch {
+	case k <= 1:
+		return UnionOf(sets...)
+	case k > len(sets):
+		return NewSizedOf[T](0)
+	case k == len(sets):
+		return IntersectOf(sets...)
+	}
+	var values []T
+	// a value in k sets is in at least one of the first len(sets)-k+1, and is
+	// only considered from the first set which has it
+	for i, set := range sets[:len(sets)-k+1] {
+		for value := range set.All() {
+			if inAny(sets[:i], value) == false && countIn(sets[i+1:], value, k-1) == k-1 {
+				values = append(values, value)
+			}
+		}
+	}
+	return FromSliceOf(values)
+}
+
+// CountsOf calls f once for every value in at least one set, with the number
+// of sets which have it. Values are visited from the first set which has them,
+// without building any intermediate set or map, so f must not modify the sets.
+func CountsOf[T Integer](f func(value T, count int), sets ...SetOf[T]) {
+	for i, set := range sets {
+		s, ok := set.(*SizedOf[T])
+		if ok == false {
+			countsSeq(f, sets, i)
+			continue
+		}
+		for _, bucket := range s.buckets {
+			for _, value := range bucket {
+				if inAny(sets[:i], value) == false {
+					f(value, 1+countIn(sets[i+1:], value, 0))
+				}
+			}
+		}
+	}
+}
+
+// AtLeast returns the values which are in at least k of the sets
+func AtLeast(k int, sets ...Set) *Sized {
+	return AtLeastOf(k, sets...)
+}
+
+// Counts calls f once for every value in at least one set, with the number of
+// sets which have it
+func Counts(f func(value int, count int), sets ...Set) {
+	CountsOf(f, sets...)
+}
+
+// IntersectCount returns the number of values in every set
+func IntersectCount(sets ...Set) int {
+	return IntersectCountOf(sets...)
+}
+
+// UnionCount returns the number of values in at least one set
+func UnionCount(sets ...Set) int {
+	return UnionCountOf(sets...)
+}
+
+// DifferenceCount returns the number of values of a which aren't in b
+func DifferenceCount(a, b Set) int {
+	return DifferenceCountOf(a, b)
+}
+
+// Jaccard returns the Jaccard index of a and b, |a ∩ b| / |a ∪ b|
+func Jaccard(a, b Set) float64 {
+	return JaccardOf(a, b)
+}
+
+// Overlap returns the overlap coefficient of a and b, |a ∩ b| / min(|a|, |b|)
+func Overlap(a, b Set) float64 {
+	return OverlapOf(a, b)
+}
+
+// intersectCount returns the number of values in both a and b
+func intersectCount[T Integer](a, b SetOf[T]) int {
+	count := 0
+	if x, y, ok := aligned(a, b); ok {
+		for i, bucket := range x.buckets {
+			count += intersectionCount(bucket, y.buckets[i])
+		}
+		return count
+	}
+	if b.Len() < a.Len() {
+		a, b = b, a
+	}
+	s, ok := a.(*SizedOf[T])
+	if ok == false {
+		return a.Len() - countNotInAny(a, SetsOf[T]{b})
+	}
+	for _, bucket := range s.buckets {
+		for _, value := range bucket {
+			if b.Exists(value) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// countInAll returns the number of values of sets[driver] in every other set
+func countInAll[T Integer](sets SetsOf[T], driver int) int {
+	count := 0
+	for value := range sets[driver].All() {
+		if inAll(sets, driver, value) {
+			count++
+		}
+	}
+	return count
+}
+
+// countNotInAny returns the number of values of set which aren't in any of others
+func countNotInAny[T Integer](set SetOf[T], others SetsOf[T]) int {
+	count := 0
+	for value := range set.All() {
+		if inAny(others, value) == false {
+			count++
+		}
+	}
+	return count
+}
+
+// countsSeq calls f for the values of sets[i] which aren't in an earlier set
+func countsSeq[T Integer](f func(value T, count int), sets SetsOf[T], i int) {
+	for value := range sets[i].All() {
+		if inAny(sets[:i], value) == false {
+			f(value, 1+countIn(sets[i+1:], value, 0))
+		}
+	}
+}
+
+// countIn returns the number of sets which have value, stopping once it
+// reaches limit (0 for no limit)
+func countIn[T Integer](sets SetsOf[T], value T, limit int) int {
+	count := 0
+	for _, set := range sets {
+		if set.Exists(value) {
+			if count++; count == limit {
+				break
+			}
+		}
+	}
+	return count
+}
+
+// inAll returns true if value is in every set except sets[skip]
+func inAll[T Integer](sets SetsOf[T], skip int, value T) bool {
+	for i, set := range sets {
+		if i != skip && set.Exists(value) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// inAny returns true if value is in at least one set
+func inAny[T Integer](sets SetsOf[T], value T) bool {
+	for _, set := range sets {
+		if set.Exists(value) {
+			return true
+		}
+	}
+	return false
+}
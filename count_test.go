@@ -0,0 +1,139 @@
+package intset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_IntersectCount(t *testing.T) {
+	a, b := pair(NewSized(10), NewSized(10))
+	c := NewSized(1000)
+	c.Set(4)
+	AssertEqual(t, IntersectCount(a, b), 2)
+	AssertEqual(t, IntersectCount(a, b.Freeze()), 2)
+	AssertEqual(t, IntersectCount(a, b, c), 1)
+	AssertEqual(t, IntersectCount(a.Freeze(), b.Freeze(), c.Freeze()), 1)
+	AssertEqual(t, IntersectCount(a), 4)
+	AssertEqual(t, IntersectCount(), 0)
+}
+
+func Test_UnionCount(t *testing.T) {
+	a, b := pair(NewSized32(10), NewSized32(10))
+	c := NewSized32(1000)
+	c.Set(100)
+	c.Set(1)
+	AssertEqual(t, UnionCount32(a, b), 6)
+	AssertEqual(t, UnionCount32(a.Freeze(), b), 6)
+	AssertEqual(t, UnionCount32(a, b, c), 7)
+	AssertEqual(t, UnionCount32(), 0)
+}
+
+func Test_DifferenceCount(t *testing.T) {
+	a, b := pair(NewRune(10), NewRune(10))
+	AssertEqual(t, DifferenceCountRune(a, b), 2)
+	AssertEqual(t, DifferenceCountRune(a, a), 0)
+	AssertEqual(t, DifferenceCountRune(a, NewRune(10)), 4)
+}
+
+func Test_Similarity(t *testing.T) {
+	a, b := pair(NewSized(10), NewSized(10))
+	AssertEqual(t, Jaccard(a, b), 2.0/6.0)
+	AssertEqual(t, Overlap(a, b), 0.5)
+	b.Remove(5)
+	b.Remove(6)
+	AssertEqual(t, Overlap(a, b), 1.0)
+	AssertEqual(t, Jaccard(a, a), 1.0)
+	AssertEqual(t, Jaccard(NewSized(1), NewSized(1)), 0.0)
+	AssertEqual(t, Overlap(a, NewSized(1)), 0.0)
+}
+
+func Test_Counts_MatchSets(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for round := 0; round < 50; round++ {
+		sets := make(Sets, 3)
+		for i := range sets {
+			s := NewSized(4 << r.Intn(6))
+			for j := 0; j < 150; j++ {
+				s.Set(r.Intn(400))
+			}
+			sets[i] = s
+		}
+		AssertEqual(t, IntersectCount(sets...), Intersect(sets...).Len())
+		AssertEqual(t, UnionCount(sets...), Union(sets...).Len())
+		AssertEqual(t, IntersectCount(sets[:2]...), Intersect(sets[:2]...).Len())
+		AssertEqual(t, UnionCount(sets[1:]...), Union(sets[1:]...).Len())
+		AssertEqual(t, DifferenceCount(sets[0], sets[1]), Difference(sets[0], sets[1]).Len())
+	}
+}
+
+func Test_Counts_DoNotAllocate(t *testing.T) {
+	a, b := pair(NewSized(10), NewSized(100))
+	sets := Sets{a, b}
+	allocs := testing.AllocsPerRun(100, func() {
+		IntersectCount(sets...)
+		UnionCount(sets...)
+		DifferenceCount(a, b)
+		Jaccard(a, b)
+		Overlap(a, b)
+	})
+	AssertEqual(t, allocs, 0.0)
+}
+
+func Test_AtLeastAndCounts_MatchOracle(t *testing.T) {
+	r := rand.New(rand.NewSource(20))
+	for round := 0; round < 50; round++ {
+		sets := make(Sets, 1+r.Intn(5))
+		oracle := make(map[int]int)
+		for i := range sets {
+			s := NewSized(4 << r.Intn(6))
+			for j := 0; j < 100; j++ {
+				value := r.Intn(300)
+				if s.Exists(value) == false {
+					s.Set(value)
+					oracle[value]++
+				}
+			}
+			sets[i] = s
+			if i%2 == 1 {
+				sets[i] = s.Freeze()
+			}
+		}
+
+		seen := 0
+		Counts(func(value int, count int) {
+			AssertEqual(t, count, oracle[value])
+			seen++
+		}, sets...)
+		AssertEqual(t, seen, len(oracle))
+
+		for k := 0; k <= len(sets)+1; k++ {
+			s := AtLeast(k, sets...)
+			expected := 0
+			for value, count := range oracle {
+				if count >= k {
+					AssertTrue(t, s.Exists(value))
+					expected++
+				}
+			}
+			AssertEqual(t, s.Len(), expected)
+		}
+	}
+}
+
+func Test_AtLeastAndCounts_OtherTypes(t *testing.T) {
+	a, b := pair(NewSized32(10), NewSized32(10))
+	c := NewSized32(10)
+	c.Set(1)
+	c.Set(6)
+	AssertEqual(t, AtLeast32(2, a, b, c).Len(), 4)
+	total := 0
+	Counts32(func(value uint32, count int) { total += count }, a, b, c)
+	AssertEqual(t, total, 10)
+
+	x, y := pair(NewRune(10), NewRune(10))
+	AssertEqual(t, AtLeastRune(2, x, y).Len(), 2)
+	AssertEqual(t, AtLeastRune(3, x, y).Len(), 0)
+	CountsRune(func(value rune, count int) {
+		AssertEqual(t, count == 2, value == 3 || value == 4)
+	}, x, y)
+}
@@ -0,0 +1,141 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+)
+
+const cursorVersion = 1
+
+var (
+	// ErrInvalidCursor is returned when resuming from a malformed token
+	ErrInvalidCursor = errors.New("intset: invalid cursor")
+	// ErrStaleCursor is returned when the set was resized since the cursor was
+	// created, which invalidates its position
+	ErrStaleCursor = errors.New("intset: stale cursor")
+)
+
+// CursorOf is a position within a set, used to page through its values.
+//
+// Paging visits buckets in order, and values within a bucket in ascending
+// order. Values present for the whole iteration are returned exactly once;
+// values added or removed between pages are returned if they're added ahead
+// of the cursor and skipped otherwise. No value is ever returned twice.
+// Resizing the set between pages makes the cursor stale.
+type CursorOf[T Integer] struct {
+	set    *SizedOf[T]
+	mask   uint64
+	bucket uint64
+	last   T
+	resume bool
+	err    error
+}
+
+// Cursor is a position within an int set
+type Cursor = CursorOf[int]
+
+// Cursor32 is a position within a uint32 set
+type Cursor32 = CursorOf[uint32]
+
+// CursorRune is a position within a rune set
+type CursorRune = CursorOf[rune]
+
+// EachUntil iterates through the set items and applies function f to each set
+// item until f returns false
+func (s *SizedOf[T]) EachUntil(f func(value T) bool) {
+	for _, bucket := range s.buckets {
+		for _, value := range bucket {
+			if f(value) == false {
+				return
+			}
+		}
+	}
+}
+
+// Cursor returns a cursor positioned at the start of the set
+func (s *SizedOf[T]) Cursor() CursorOf[T] {
+	return CursorOf[T]{set: s, mask: s.mask}
+}
+
+// Resume returns a cursor at the position described by a token from Token. On
+// error, the returned cursor is done and its Err is the same error.
+func (s *SizedOf[T]) Resume(token string) (CursorOf[T], error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) != 26 || data[0] != cursorVersion || data[1] > 1 {
+		return CursorOf[T]{err: ErrInvalidCursor}, ErrInvalidCursor
+	}
+	c := CursorOf[T]{
+		set:    s,
+		resume: data[1] == 1,
+		mask:   binary.LittleEndian.Uint64(data[2:]),
+		bucket: binary.LittleEndian.Uint64(data[10:]),
+		last:   T(binary.LittleEndian.Uint64(data[18:])),
+	}
+	if c.bucket > c.mask+1 {
+		return CursorOf[T]{err: ErrInvalidCursor}, ErrInvalidCursor
+	}
+	if c.mask != s.mask {
+		return CursorOf[T]{err: ErrStaleCursor}, ErrStaleCursor
+	}
+	return c, nil
+}
+
+// Next returns up to n values following the cursor, along with a cursor
+// positioned after them. A stale cursor returns no values.
+func (c CursorOf[T]) Next(n int) ([]T, CursorOf[T]) {
+	if n <= 0 || c.Done() {
+		return nil, c
+	}
+	if c.mask != c.set.mask {
+		c.err = ErrStaleCursor
+		return nil, c
+	}
+
+	values := make([]T, 0, n)
+	for c.bucket <= c.mask && len(values) < n {
+		bucket := c.set.buckets[c.bucket]
+		if c.resume {
+			position, exists := c.set.index(c.last, bucket)
+			if exists {
+				position++
+			}
+			bucket = bucket[position:]
+		}
+		if room := n - len(values); len(bucket) > room {
+			values = append(values, bucket[:room]...)
+			c.last, c.resume = values[len(values)-1], true
+			break
+		}
+		values = append(values, bucket...)
+		c.bucket++
+		c.resume = false
+	}
+	return values, c
+}
+
+// Done returns true once every value has been returned, or the cursor is stale
+// or invalid. A zero CursorOf, which isn't positioned within any set, is done.
+func (c CursorOf[T]) Done() bool {
+	return c.err != nil || c.set == nil || c.bucket > c.mask
+}
+
+// Err returns ErrStaleCursor if the set was resized since the cursor was
+// created, or the error of Resume
+func (c CursorOf[T]) Err() error {
+	return c.err
+}
+
+// Token serializes the cursor's position as an opaque, URL safe string
+func (c CursorOf[T]) Token() string {
+	data := make([]byte, 26)
+	data[0] = cursorVersion
+	if c.resume {
+		data[1] = 1
+	}
+	binary.LittleEndian.PutUint64(data[2:], c.mask)
+	binary.LittleEndian.PutUint64(data[10:], c.bucket)
+	binary.LittleEndian.PutUint64(data[18:], uint64(c.last))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
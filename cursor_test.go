@@ -0,0 +1,124 @@
+package intset
+
+import "testing"
+
+func Test_EachUntil_Stops(t *testing.T) {
+	s := NewSized(100)
+	for i := 0; i < 100; i++ {
+		s.Set(i)
+	}
+	count := 0
+	s.EachUntil(func(value int) bool {
+		count++
+		return count < 7
+	})
+	AssertEqual(t, count, 7)
+}
+
+func Test_Cursor_PagesThroughEveryValue(t *testing.T) {
+	s := NewSized(100)
+	for i := 0; i < 1000; i++ {
+		s.Set(i * 7)
+	}
+	seen := make(map[int]struct{})
+	c := s.Cursor()
+	pages := 0
+	for c.Done() == false {
+		var page []int
+		page, c = c.Next(33)
+		AssertTrue(t, len(page) <= 33)
+		for _, value := range page {
+			_, exists := seen[value]
+			AssertFalse(t, exists)
+			seen[value] = struct{}{}
+		}
+		pages++
+	}
+	AssertEqual(t, len(seen), 1000)
+	AssertEqual(t, pages, 31)
+	AssertTrue(t, c.Err() == nil)
+
+	page, c := c.Next(10)
+	AssertEqual(t, len(page), 0)
+	AssertTrue(t, c.Done())
+}
+
+func Test_Cursor_ResumesFromToken(t *testing.T) {
+	s := NewSized32(10)
+	for i := uint32(0); i < 100; i++ {
+		s.Set(i)
+	}
+	var all []uint32
+	page, c := s.Cursor().Next(10)
+	all = append(all, page...)
+	for c.Done() == false {
+		resumed, err := s.Resume(c.Token())
+		AssertTrue(t, err == nil)
+		page, c = resumed.Next(10)
+		all = append(all, page...)
+	}
+	AssertEqual(t, len(all), 100)
+
+	done, err := s.Resume(c.Token())
+	AssertTrue(t, err == nil)
+	AssertTrue(t, done.Done())
+}
+
+func Test_Cursor_ToleratesMutationBetweenPages(t *testing.T) {
+	s := NewSized(4)
+	for i := 0; i < 10; i++ {
+		s.Set(i * 2)
+	}
+	page, c := s.Cursor().Next(3)
+	assertSequence(t, page, []int{0, 2, 4})
+
+	// removing the last value returned, and values ahead of the cursor
+	s.Remove(4)
+	s.Remove(8)
+	// adding values behind and ahead of the cursor
+	s.Set(1)
+	s.Set(5)
+	page, c = c.Next(100)
+	assertSequence(t, page, []int{5, 6, 10, 12, 14, 16, 18})
+	AssertTrue(t, c.Done())
+}
+
+func Test_Cursor_BecomesStaleOnResize(t *testing.T) {
+	s := NewRune(16)
+	for i := rune(0); i < 16; i++ {
+		s.Set(i)
+	}
+	_, c := s.Cursor().Next(2)
+	token := c.Token()
+	s.Resize(1000)
+
+	page, c := c.Next(2)
+	AssertEqual(t, len(page), 0)
+	AssertTrue(t, c.Done())
+	AssertTrue(t, c.Err() == ErrStaleCursor)
+
+	c, err := s.Resume(token)
+	AssertTrue(t, err == ErrStaleCursor)
+	AssertTrue(t, c.Done())
+	AssertTrue(t, c.Err() == ErrStaleCursor)
+}
+
+func Test_Cursor_RejectsInvalidToken(t *testing.T) {
+	s := NewSized(10)
+	for _, token := range []string{"", "!!", "AQ", s.Cursor().Token() + "A"} {
+		c, err := s.Resume(token)
+		AssertTrue(t, err == ErrInvalidCursor)
+		AssertTrue(t, c.Done())
+		AssertTrue(t, c.Err() == ErrInvalidCursor)
+		values, _ := c.Next(10)
+		AssertEqual(t, len(values), 0)
+	}
+}
+
+func Test_Cursor_ZeroValueIsDone(t *testing.T) {
+	var c Cursor
+	AssertTrue(t, c.Done())
+	values, c := c.Next(10)
+	AssertEqual(t, len(values), 0)
+	AssertTrue(t, c.Err() == nil)
+}
@@ -0,0 +1,168 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidRange is returned when decoding a text range whose start is
+	// greater than its end
+	ErrInvalidRange = errors.New("intset: invalid range")
+	// ErrTooManyValues is returned when decoding text whose ranges add up to
+	// more than MaxTextValues values
+	ErrTooManyValues = errors.New("intset: too many values")
+)
+
+// MaxTextValues is the most values UnmarshalText will add to a set, since
+// every value of a range is stored individually
+const MaxTextValues = 1 << 26
+
+// MarshalJSON implements json.Marshaler, encoding the set as a sorted array
+func (s *SizedOf[T]) MarshalJSON() ([]byte, error) {
+	values := s.sorted()
+	b := make([]byte, 0, 2+len(values)*8)
+	b = append(b, '[')
+	for i, value := range values {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendText(b, value)
+	}
+	return append(b, ']'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the content of the set
+// with an array of values. The set is resized for the number of values.
+func (s *SizedOf[T]) UnmarshalJSON(data []byte) error {
+	var numbers []json.Number
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		return err
+	}
+	if numbers == nil {
+		return nil
+	}
+	values := make([]T, len(numbers))
+	for i, number := range numbers {
+		value, err := parseText[T](string(number))
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	s.reset(len(values))
+	for _, value := range values {
+		s.Set(value)
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the set as a sorted
+// comma separated list, collapsing consecutive values into ranges: 1-5,9,12
+func (s *SizedOf[T]) MarshalText() ([]byte, error) {
+	values := s.sorted()
+	b := make([]byte, 0, len(values)*4)
+	for i := 0; i < len(values); {
+		start := values[i]
+		j := i + 1
+		for j < len(values) && values[j] == values[j-1]+1 {
+			j++
+		}
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendText(b, start)
+		if j-i > 1 {
+			b = append(b, '-')
+			b = appendText(b, values[j-1])
+		}
+		i = j
+	}
+	return b, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the content of
+// the set with the values described by text. See MarshalText.
+func (s *SizedOf[T]) UnmarshalText(text []byte) error {
+	type span struct{ from, to T }
+	var spans []span
+	var size uint64
+	if len(text) > 0 {
+		for _, part := range strings.Split(string(text), ",") {
+			from, to := part, part
+			// a leading '-' is a sign, not a range separator
+			if len(part) > 1 {
+				if i := strings.IndexByte(part[1:], '-'); i != -1 {
+					from, to = part[:i+1], part[i+2:]
+				}
+			}
+			f, err := parseText[T](from)
+			if err != nil {
+				return err
+			}
+			t, err := parseText[T](to)
+			if err != nil {
+				return err
+			}
+			if f > t {
+				return ErrInvalidRange
+			}
+			// the difference can't overflow, but adding 1 to it can
+			if uint64(t)-uint64(f) >= MaxTextValues-size {
+				return ErrTooManyValues
+			}
+			spans = append(spans, span{f, t})
+			size += uint64(t) - uint64(f) + 1
+		}
+	}
+	s.reset(int(size))
+	for _, span := range spans {
+		for value := span.from; ; value++ {
+			s.Set(value)
+			if value == span.to {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder using the binary format
+func (s *SizedOf[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary format
+func (s *SizedOf[T]) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+// sorted returns the values of the set in ascending order
+func (s *SizedOf[T]) sorted() []T {
+	values := make([]T, 0, s.length)
+	for _, bucket := range s.buckets {
+		values = append(values, bucket...)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+func appendText[T Integer](b []byte, value T) []byte {
+	if isSigned[T]() {
+		return strconv.AppendInt(b, int64(value), 10)
+	}
+	return strconv.AppendUint(b, uint64(value), 10)
+}
+
+func parseText[T Integer](text string) (T, error) {
+	if isSigned[T]() {
+		value, err := strconv.ParseInt(text, 10, bitsOf[T]())
+		return T(value), err
+	}
+	value, err := strconv.ParseUint(text, 10, bitsOf[T]())
+	return T(value), err
+}
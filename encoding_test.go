@@ -0,0 +1,133 @@
+package intset
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ json.Marshaler           = (*Sized)(nil)
+	_ json.Unmarshaler         = (*Sized32)(nil)
+	_ encoding.TextMarshaler   = (*Rune)(nil)
+	_ encoding.TextUnmarshaler = (*Rune)(nil)
+	_ gob.GobEncoder           = (*Sized)(nil)
+	_ gob.GobDecoder           = (*Sized)(nil)
+)
+
+func Test_JSON_MarshalsSortedArray(t *testing.T) {
+	s := NewSized(100)
+	for _, value := range []int{9, -3, 12, 1, 1000} {
+		s.Set(value)
+	}
+	data, err := json.Marshal(s)
+	AssertTrue(t, err == nil)
+	AssertEqual(t, string(data), "[-3,1,9,12,1000]")
+
+	data, _ = json.Marshal(NewSized32(10))
+	AssertEqual(t, string(data), "[]")
+}
+
+func Test_JSON_UnmarshalsAndSizes(t *testing.T) {
+	var s Sized
+	AssertTrue(t, json.Unmarshal([]byte("[5, -2, 5, 300]"), &s) == nil)
+	AssertEqual(t, s.Len(), 3)
+	AssertTrue(t, s.Exists(-2))
+	AssertTrue(t, s.Exists(300))
+
+	values := make([]byte, 0, 8192)
+	values = append(values, '[')
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			values = append(values, ',')
+		}
+		values = appendText(values, i)
+	}
+	values = append(values, ']')
+	AssertTrue(t, json.Unmarshal(values, &s) == nil)
+	AssertEqual(t, s.Len(), 1000)
+	AssertEqual(t, len(s.buckets), 256)
+	AssertFalse(t, s.Exists(-2))
+}
+
+func Test_JSON_UnmarshalRejectsOutOfRange(t *testing.T) {
+	s := NewSizedOf[uint8](10)
+	AssertTrue(t, json.Unmarshal([]byte("[1, 256]"), s) != nil)
+	AssertTrue(t, json.Unmarshal([]byte("[-1]"), s) != nil)
+	AssertTrue(t, json.Unmarshal([]byte("[1.5]"), s) != nil)
+	AssertTrue(t, json.Unmarshal([]byte("[1, 255]"), s) == nil)
+	AssertEqual(t, s.Len(), 2)
+}
+
+func Test_Text_MarshalsRanges(t *testing.T) {
+	s := NewSized(100)
+	for _, value := range []int{1, 2, 3, 4, 5, 9, 12, 13, -4, -3, -1} {
+		s.Set(value)
+	}
+	text, err := s.MarshalText()
+	AssertTrue(t, err == nil)
+	AssertEqual(t, string(text), "-4--3,-1,1-5,9,12-13")
+
+	text, _ = NewRune(10).MarshalText()
+	AssertEqual(t, string(text), "")
+}
+
+func Test_Text_RoundTrips(t *testing.T) {
+	var s Sized
+	AssertTrue(t, s.UnmarshalText([]byte("-4--3,-1,1-5,9,12-13")) == nil)
+	AssertEqual(t, s.Len(), 11)
+	for _, value := range []int{-4, -3, -1, 1, 2, 3, 4, 5, 9, 12, 13} {
+		AssertTrue(t, s.Exists(value))
+	}
+	text, _ := s.MarshalText()
+	AssertEqual(t, string(text), "-4--3,-1,1-5,9,12-13")
+
+	AssertTrue(t, s.UnmarshalText(nil) == nil)
+	AssertEqual(t, s.Len(), 0)
+}
+
+func Test_Text_RangeEndingAtMax(t *testing.T) {
+	s := NewSizedOf[uint8](10)
+	AssertTrue(t, s.UnmarshalText([]byte("250-255")) == nil)
+	AssertEqual(t, s.Len(), 6)
+	AssertTrue(t, s.Exists(255))
+}
+
+func Test_Text_RejectsInvalid(t *testing.T) {
+	var s Sized32
+	AssertTrue(t, s.UnmarshalText([]byte("5-1")) == ErrInvalidRange)
+	AssertTrue(t, s.UnmarshalText([]byte("1,,2")) != nil)
+	AssertTrue(t, s.UnmarshalText([]byte("a-2")) != nil)
+	AssertTrue(t, s.UnmarshalText([]byte("-1")) != nil)
+}
+
+func Test_Text_RejectsTooManyValues(t *testing.T) {
+	var u SizedOf[uint64]
+	AssertTrue(t, u.UnmarshalText([]byte("0-18446744073709551615")) == ErrTooManyValues)
+
+	var s SizedOf[int64]
+	AssertTrue(t, s.UnmarshalText([]byte("0-9000000000000000000")) == ErrTooManyValues)
+	AssertTrue(t, s.UnmarshalText([]byte("-9223372036854775808-9223372036854775807")) == ErrTooManyValues)
+	AssertTrue(t, s.UnmarshalText([]byte("0-67108863,100000000")) == ErrTooManyValues)
+	AssertEqual(t, s.Len(), 0)
+}
+
+func Test_Gob_RoundTrips(t *testing.T) {
+	type payload struct {
+		Name string
+		IDs  *Sized
+	}
+	s := NewSized(100)
+	for i := 0; i < 100; i++ {
+		s.Set(i * 31)
+	}
+	var buf bytes.Buffer
+	AssertTrue(t, gob.NewEncoder(&buf).Encode(payload{"ids", s}) == nil)
+
+	var p payload
+	AssertTrue(t, gob.NewDecoder(&buf).Decode(&p) == nil)
+	AssertEqual(t, p.Name, "ids")
+	AssertSameLayout(t, p.IDs, s)
+}
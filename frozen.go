@@ -0,0 +1,173 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unsafe"
+)
+
+// The frozen format is little endian, with every section 8 byte aligned so
+// that it can be used in place once mapped:
+//
+//	magic    [4]byte "isfz"
+//	version  uint8
+//	flags    uint8   flagHashed
+//	reserved [2]byte
+//	buckets  uint64  number of buckets (mask + 1)
+//	length   uint64  number of values
+//	offsets  [buckets+1]uint64, bucket i is values[offsets[i]:offsets[i+1]]
+//	values   [length]int64
+const (
+	frozenVersion    = 1
+	frozenHeaderSize = 24
+)
+
+var frozenMagic = [4]byte{'i', 's', 'f', 'z'}
+
+// Frozen is a read-only int set backed by a file written with WriteFrozen.
+// Lookups operate directly on the mapped file.
+type Frozen struct {
+	FrozenSized
+	data []byte
+}
+
+// WriteFrozen writes the set in the format read by LoadMapped. Values are
+// stored as 64 bit integers; ErrValueOverflow is returned, before anything is
+// written, if the set holds a value greater than math.MaxInt64.
+func (s *SizedOf[T]) WriteFrozen(w io.Writer) (int64, error) {
+	if bitsOf[T]() == 64 && isSigned[T]() == false {
+		for _, bucket := range s.buckets {
+			for _, value := range bucket {
+				if int64(value) < 0 {
+					return 0, ErrValueOverflow
+				}
+			}
+		}
+	}
+	header := make([]byte, frozenHeaderSize+(len(s.buckets)+1)*8)
+	copy(header, frozenMagic[:])
+	header[4] = frozenVersion
+	if s.hash != nil {
+		header[5] = flagHashed
+	}
+	binary.LittleEndian.PutUint64(header[8:], uint64(len(s.buckets)))
+	binary.LittleEndian.PutUint64(header[16:], uint64(s.length))
+	offset := 0
+	for i, bucket := range s.buckets {
+		binary.LittleEndian.PutUint64(header[frozenHeaderSize+i*8:], uint64(offset))
+		offset += len(bucket)
+	}
+	binary.LittleEndian.PutUint64(header[frozenHeaderSize+len(s.buckets)*8:], uint64(offset))
+
+	n, err := w.Write(header)
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	chunk := make([]byte, 0, 4096)
+	for _, bucket := range s.buckets {
+		for _, value := range bucket {
+			if len(chunk) == cap(chunk) {
+				n, err = w.Write(chunk)
+				total += int64(n)
+				if err != nil {
+					return total, err
+				}
+				chunk = chunk[:0]
+			}
+			chunk = appendValue(chunk, int64(value), 8)
+		}
+	}
+	n, err = w.Write(chunk)
+	return total + int64(n), err
+}
+
+// LoadMapped maps a file written by WriteFrozen into memory. The returned set
+// must be closed to release the mapping.
+func LoadMapped(path string) (*Frozen, error) {
+	return LoadMappedConfig(path, Default)
+}
+
+// LoadMappedConfig maps a file written by WriteFrozen into memory, using the
+// Hasher of config. The returned set must be closed to release the mapping.
+func LoadMappedConfig(path string, config *Config) (*Frozen, error) {
+	data, err := mmap(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := newFrozen(data, config.hash)
+	if err != nil {
+		munmap(data)
+		return nil, err
+	}
+	return f, nil
+}
+
+func newFrozen(data []byte, hash func(uint64) uint64) (*Frozen, error) {
+	if len(data) < frozenHeaderSize || bytes.Equal(data[:4], frozenMagic[:]) == false {
+		return nil, ErrInvalidFormat
+	}
+	if data[4] != frozenVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	if (data[5]&flagHashed != 0) != (hash != nil) {
+		return nil, ErrHasherMismatch
+	}
+	count := binary.LittleEndian.Uint64(data[8:])
+	length := binary.LittleEndian.Uint64(data[16:])
+	if count == 0 || count&(count-1) != 0 || count > uint64(len(data))/8 || length > uint64(len(data))/8 {
+		return nil, ErrInvalidFormat
+	}
+	start := uint64(frozenHeaderSize + (count+1)*8)
+	if uint64(len(data)) != start+length*8 {
+		return nil, ErrInvalidFormat
+	}
+
+	f := &Frozen{data: data}
+	f.mask, f.hash = count-1, hash
+	if nativeLittleEndian64() {
+		f.offsets = unsafe.Slice((*uint64)(unsafe.Pointer(&data[frozenHeaderSize])), count+1)
+		if length > 0 {
+			f.values = unsafe.Slice((*int)(unsafe.Pointer(&data[start])), length)
+		}
+	} else {
+		f.offsets = make([]uint64, count+1)
+		for i := range f.offsets {
+			f.offsets[i] = binary.LittleEndian.Uint64(data[frozenHeaderSize+i*8:])
+		}
+		f.values = make([]int, length)
+		for i := range f.values {
+			f.values[i] = int(binary.LittleEndian.Uint64(data[start+uint64(i)*8:]))
+		}
+	}
+
+	if f.offsets[0] != 0 || f.offsets[count] != length {
+		return nil, ErrInvalidFormat
+	}
+	for i := uint64(0); i < count; i++ {
+		if f.offsets[i] > f.offsets[i+1] {
+			return nil, ErrInvalidFormat
+		}
+	}
+	return f, nil
+}
+
+// Close releases the mapped file. The set must not be used afterwards.
+func (f *Frozen) Close() error {
+	data := f.data
+	f.data, f.offsets, f.values = nil, nil, nil
+	if data == nil {
+		return nil
+	}
+	return munmap(data)
+}
+
+// nativeLittleEndian64 returns true if the mapped bytes can be used in place
+// as []uint64 and []int
+func nativeLittleEndian64() bool {
+	x := uint16(1)
+	return unsafe.Sizeof(int(0)) == 8 && *(*byte)(unsafe.Pointer(&x)) == 1
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import "os"
+
+// without mmap support, the file is read into a single allocation
+func mmap(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func munmap(data []byte) error {
+	return nil
+}
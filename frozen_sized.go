@@ -0,0 +1,64 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+// FrozenSizedOf is an immutable set which packs all of its values into a
+// single array, with bucket i holding values[offsets[i]:offsets[i+1]]
+type FrozenSizedOf[T Integer] struct {
+	mask    uint64
+	offsets []uint64
+	values  []T
+	hash    func(uint64) uint64
+}
+
+// FrozenSized is an immutable int set
+type FrozenSized = FrozenSizedOf[int]
+
+// FrozenSized32 is an immutable uint32 set
+type FrozenSized32 = FrozenSizedOf[uint32]
+
+// FrozenRune is an immutable rune set
+type FrozenRune = FrozenSizedOf[rune]
+
+// Freeze returns an immutable copy of the set with a single contiguous backing
+// array, which is faster to probe and cheaper to garbage collect
+func (s *SizedOf[T]) Freeze() *FrozenSizedOf[T] {
+	f := &FrozenSizedOf[T]{
+		mask:    s.mask,
+		offsets: make([]uint64, len(s.buckets)+1),
+		values:  make([]T, 0, s.length),
+		hash:    s.hash,
+	}
+	for i, bucket := range s.buckets {
+		f.offsets[i] = uint64(len(f.values))
+		f.values = append(f.values, bucket...)
+	}
+	f.offsets[len(s.buckets)] = uint64(len(f.values))
+	return f
+}
+
+// Exists returns true if the value exists in the set
+func (f *FrozenSizedOf[T]) Exists(value T) bool {
+	h := uint64(value)
+	if f.hash != nil {
+		h = f.hash(h)
+	}
+	b := h & f.mask
+	for _, v := range f.values[f.offsets[b]:f.offsets[b+1]] {
+		if v >= value {
+			return v == value
+		}
+	}
+	return false
+}
+
+// Len returns the total number of elements in the set
+func (f *FrozenSizedOf[T]) Len() int {
+	return len(f.values)
+}
+
+// Each iterates through the set items and applies function fn to each set item
+func (f *FrozenSizedOf[T]) Each(fn func(value T)) {
+	for _, value := range f.values {
+		fn(value)
+	}
+}
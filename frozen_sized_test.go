@@ -0,0 +1,70 @@
+package intset
+
+import "testing"
+
+var (
+	_ Set     = (*FrozenSized)(nil)
+	_ Set32   = (*FrozenSized32)(nil)
+	_ SetRune = (*FrozenRune)(nil)
+)
+
+func Test_FrozenSized_MatchesSource(t *testing.T) {
+	s := NewSized(1000)
+	for i := -500; i < 500; i++ {
+		s.Set(i * 3)
+	}
+	f := s.Freeze()
+	AssertEqual(t, f.Len(), 1000)
+	for i := -500; i < 500; i++ {
+		AssertTrue(t, f.Exists(i*3))
+		AssertFalse(t, f.Exists(i*3+1))
+	}
+	count := 0
+	f.Each(func(value int) {
+		AssertTrue(t, s.Exists(value))
+		count++
+	})
+	AssertEqual(t, count, 1000)
+}
+
+func Test_FrozenSized_IsIndependentOfSource(t *testing.T) {
+	s := NewSized32Config(16, NewConfig().Hasher(Murmur3Hash))
+	s.Set(1)
+	s.Set(2)
+	f := s.Freeze()
+	s.Remove(1)
+	s.Set(3)
+	AssertEqual(t, f.Len(), 2)
+	AssertTrue(t, f.Exists(1))
+	AssertTrue(t, f.Exists(2))
+	AssertFalse(t, f.Exists(3))
+}
+
+func Test_FrozenSized_Empty(t *testing.T) {
+	f := NewRune(10).Freeze()
+	AssertEqual(t, f.Len(), 0)
+	AssertFalse(t, f.Exists('a'))
+}
+
+func Test_FrozenSized_Intersects(t *testing.T) {
+	s1 := NewSized(10)
+	s2 := NewSized(10)
+	for i := 0; i < 10; i++ {
+		s1.Set(i)
+		s2.Set(i * 2)
+	}
+	s := Intersect(s1.Freeze(), s2.Freeze())
+	AssertEqual(t, s.Len(), 5)
+}
+
+func Benchmark_FrozenSizedDenseExists(b *testing.B) {
+	s := NewSized(1000000)
+	for i := 0; i < 1000000; i++ {
+		s.Set(i)
+	}
+	f := s.Freeze()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Exists(i % 1000000)
+	}
+}
@@ -0,0 +1,117 @@
+package intset
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var _ Set = (*Frozen)(nil)
+
+func Test_Frozen_LoadsMappedSet(t *testing.T) {
+	s := NewSized(1000)
+	for i := -500; i < 500; i++ {
+		s.Set(i * 3)
+	}
+	f := writeAndMap(t, s, Default)
+	defer f.Close()
+
+	AssertEqual(t, f.Len(), 1000)
+	for i := -500; i < 500; i++ {
+		AssertTrue(t, f.Exists(i*3))
+		AssertFalse(t, f.Exists(i*3+1))
+	}
+	count := 0
+	f.Each(func(value int) {
+		AssertTrue(t, s.Exists(value))
+		count++
+	})
+	AssertEqual(t, count, 1000)
+}
+
+func Test_Frozen_LoadsEmptySet(t *testing.T) {
+	f := writeAndMap(t, NewSized(10), Default)
+	defer f.Close()
+	AssertEqual(t, f.Len(), 0)
+	AssertFalse(t, f.Exists(0))
+}
+
+func Test_Frozen_UsesHasher(t *testing.T) {
+	config := NewConfig().Hasher(FibonacciHash)
+	s := NewSized32Config(100, config)
+	for i := uint32(0); i < 100; i++ {
+		s.Set(i << 10)
+	}
+	path := filepath.Join(t.TempDir(), "set")
+	file, _ := os.Create(path)
+	_, err := s.WriteFrozen(file)
+	AssertTrue(t, err == nil)
+	file.Close()
+
+	_, err = LoadMapped(path)
+	AssertTrue(t, err == ErrHasherMismatch)
+
+	f, err := LoadMappedConfig(path, config)
+	AssertTrue(t, err == nil)
+	defer f.Close()
+	for i := 0; i < 100; i++ {
+		AssertTrue(t, f.Exists(i<<10))
+	}
+}
+
+func Test_Frozen_RejectsValuesOverflowingInt64(t *testing.T) {
+	s := NewSizedOf[uint64](10)
+	s.Set(math.MaxInt64)
+	var buf bytes.Buffer
+	_, err := s.WriteFrozen(&buf)
+	AssertTrue(t, err == nil)
+
+	buf.Reset()
+	s.Set(math.MaxInt64 + 1)
+	n, err := s.WriteFrozen(&buf)
+	AssertTrue(t, err == ErrValueOverflow)
+	AssertEqual(t, n, int64(0))
+	AssertEqual(t, buf.Len(), 0)
+}
+
+func Test_Frozen_RejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "set")
+	os.WriteFile(path, []byte("not a frozen set, really not one"), 0600)
+	_, err := LoadMapped(path)
+	AssertTrue(t, err == ErrInvalidFormat)
+
+	_, err = LoadMapped(filepath.Join(t.TempDir(), "missing"))
+	AssertTrue(t, os.IsNotExist(err))
+
+	s := NewSized(10)
+	s.Set(1)
+	var buf bytes.Buffer
+	s.WriteFrozen(&buf)
+	data := buf.Bytes()
+	_, err = newFrozen(data[:len(data)-1], nil)
+	AssertTrue(t, err == ErrInvalidFormat)
+	_, err = newFrozen(append(data, 0), nil)
+	AssertTrue(t, err == ErrInvalidFormat)
+
+	data[4] = 2
+	_, err = newFrozen(data, nil)
+	AssertTrue(t, err == ErrUnsupportedVersion)
+}
+
+func writeAndMap[T Integer](t *testing.T, s *SizedOf[T], config *Config) *Frozen {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "set")
+	file, err := os.Create(path)
+	AssertTrue(t, err == nil)
+	n, err := s.WriteFrozen(file)
+	AssertTrue(t, err == nil)
+	file.Close()
+	info, _ := os.Stat(path)
+	AssertEqual(t, n, info.Size())
+
+	f, err := LoadMappedConfig(path, config)
+	AssertTrue(t, err == nil)
+	return f
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"os"
+	"syscall"
+)
+
+func mmap(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size < frozenHeaderSize || int64(int(size)) != size {
+		return nil, ErrInvalidFormat
+	}
+	return syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func munmap(data []byte) error {
+	return syscall.Munmap(data)
+}
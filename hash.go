@@ -0,0 +1,39 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+// Hashers map a value to the bits used to select its bucket. Only the low bits
+// of the result are used, so a hasher must mix its high bits downwards.
+
+// IdentityHash uses the value as-is. This is the default and is ideal for
+// values which are already random.
+func IdentityHash(value uint64) uint64 {
+	return value
+}
+
+// FibonacciHash multiplies by 2^64 / φ and folds the well mixed high bits
+// into the low bits. Cheap, and good enough for sequential values or
+// multiples of a power of two.
+func FibonacciHash(value uint64) uint64 {
+	value *= 0x9e3779b97f4a7c15
+	return value ^ (value >> 32)
+}
+
+// Murmur3Hash is the 64-bit finalizer (fmix64) of MurmurHash3
+func Murmur3Hash(value uint64) uint64 {
+	value ^= value >> 33
+	value *= 0xff51afd7ed558ccd
+	value ^= value >> 33
+	value *= 0xc4ceb9fe1a85ec53
+	value ^= value >> 33
+	return value
+}
+
+// XXHash is the 64-bit avalanche step of xxHash
+func XXHash(value uint64) uint64 {
+	value ^= value >> 33
+	value *= 0xc2b2ae3d27d4eb4f
+	value ^= value >> 29
+	value *= 0x165667b19e3779f9
+	value ^= value >> 32
+	return value
+}
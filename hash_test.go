@@ -0,0 +1,66 @@
+package intset
+
+import "testing"
+
+func Test_Hash_SpreadsStructuredValues(t *testing.T) {
+	for _, hash := range []func(uint64) uint64{FibonacciHash, Murmur3Hash, XXHash} {
+		s := NewSizedConfig(4096, NewConfig().Hasher(hash))
+		for i := 0; i < 4096; i++ {
+			s.Set(i * 1024)
+		}
+		AssertTrue(t, longestBucket(s) <= 16)
+		for i := 0; i < 4096; i++ {
+			AssertTrue(t, s.Exists(i*1024))
+			AssertFalse(t, s.Exists(i*1024+1))
+		}
+	}
+}
+
+func Test_Hash_IdentityIsDefault(t *testing.T) {
+	s := NewSizedConfig(4096, NewConfig().Hasher(IdentityHash))
+	d := NewSized(4096)
+	for i := 0; i < 4096; i++ {
+		s.Set(i * 1024)
+		d.Set(i * 1024)
+	}
+	AssertEqual(t, longestBucket(s), 4096)
+	AssertEqual(t, longestBucket(d), 4096)
+	AssertTrue(t, s.hash == nil)
+
+	// interchangeable with sets using the default
+	_, _, ok := aligned[int](s, d)
+	AssertTrue(t, ok)
+	data, _ := d.MarshalBinary()
+	l := NewSizedConfig(0, NewConfig().Hasher(IdentityHash))
+	AssertTrue(t, l.UnmarshalBinary(data) == nil)
+	AssertSameLayout(t, l, d)
+}
+
+func Test_Hash_MatchesOracle(t *testing.T) {
+	config := NewConfig().Hasher(Murmur3Hash).AutoGrow(4).AutoShrink(1)
+	for seed := int64(0); seed < 5; seed++ {
+		AssertOracle(t, NewSizedConfig(4, config), seed, 1<<12)
+		AssertOracle(t, NewSizedOfConfig[int8](4, config), seed, 256)
+	}
+}
+
+func longestBucket[T Integer](s *SizedOf[T]) int {
+	longest := 0
+	for _, bucket := range s.buckets {
+		if len(bucket) > longest {
+			longest = len(bucket)
+		}
+	}
+	return longest
+}
+
+func Benchmark_HashFibonacciSequentialExists(b *testing.B) {
+	s := NewSizedConfig(1000000, NewConfig().Hasher(FibonacciHash))
+	for i := 0; i < 1000000; i++ {
+		s.Set(i * 64)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Exists((i % 1000000) * 64)
+	}
+}
@@ -0,0 +1,216 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// IntervalsOf is a set stored as sorted, disjoint ranges of consecutive
+// values. Adding a range costs the same whatever its size, and ranges which
+// overlap or touch are coalesced.
+type IntervalsOf[T Integer] struct {
+	spans []span[T]
+	// extent is the sum of hi-lo over spans, so the set holds extent+len(spans)
+	// values. Unlike that count, it can't overflow a uint64.
+	extent uint64
+}
+
+// span is a range of consecutive values, from lo to hi inclusively
+type span[T Integer] struct {
+	lo, hi T
+}
+
+// Intervals is an int set of ranges
+type Intervals = IntervalsOf[int]
+
+// Intervals32 is a uint32 set of ranges
+type Intervals32 = IntervalsOf[uint32]
+
+// IntervalsRune is a rune set of ranges
+type IntervalsRune = IntervalsOf[rune]
+
+// NewIntervalsOf creates an empty set of ranges
+func NewIntervalsOf[T Integer]() *IntervalsOf[T] {
+	return &IntervalsOf[T]{}
+}
+
+// NewIntervals creates an empty int set of ranges
+func NewIntervals() *Intervals {
+	return NewIntervalsOf[int]()
+}
+
+// NewIntervals32 creates an empty uint32 set of ranges
+func NewIntervals32() *Intervals32 {
+	return NewIntervalsOf[uint32]()
+}
+
+// NewIntervalsRune creates an empty rune set of ranges
+func NewIntervalsRune() *IntervalsRune {
+	return NewIntervalsOf[rune]()
+}
+
+// Set adds a value to the set
+func (s *IntervalsOf[T]) Set(value T) {
+	s.AddRange(value, value)
+}
+
+// Remove returns true if the value existed in the set before being removed
+func (s *IntervalsOf[T]) Remove(value T) bool {
+	if s.Exists(value) == false {
+		return false
+	}
+	s.RemoveRange(value, value)
+	return true
+}
+
+// Exists returns true if the value exists in the set
+func (s *IntervalsOf[T]) Exists(value T) bool {
+	return s.ContainsRange(value, value)
+}
+
+// Len returns the total number of elements in the set. Ranges of 64 bit
+// types can hold more values than an int can count, in which case Len
+// returns math.MaxInt.
+func (s *IntervalsOf[T]) Len() int {
+	n, carry := bits.Add64(s.extent, uint64(len(s.spans)), 0)
+	if carry != 0 || n > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(n)
+}
+
+// Each iterates through the set items, in ascending order, and applies
+// function f to each set item
+func (s *IntervalsOf[T]) Each(f func(value T)) {
+	for _, span := range s.spans {
+		for value := span.lo; ; value++ {
+			f(value)
+			if value == span.hi {
+				break
+			}
+		}
+	}
+}
+
+// AddRange adds the values from lo to hi, inclusively
+func (s *IntervalsOf[T]) AddRange(lo, hi T) {
+	if lo > hi {
+		return
+	}
+	// spans [i, j) overlap or touch [lo, hi], and are replaced by their union
+	i := sort.Search(len(s.spans), func(k int) bool {
+		return s.spans[k].hi >= lo || s.spans[k].hi+1 == lo
+	})
+	j := sort.Search(len(s.spans), func(k int) bool {
+		return s.spans[k].lo > hi && s.spans[k].lo-1 != hi
+	})
+	if i < j {
+		lo = min(lo, s.spans[i].lo)
+		hi = max(hi, s.spans[j-1].hi)
+	}
+	s.replace(i, j, span[T]{lo, hi})
+}
+
+// RemoveRange removes the values from lo to hi, inclusively
+func (s *IntervalsOf[T]) RemoveRange(lo, hi T) {
+	if lo > hi {
+		return
+	}
+	// spans [i, j) overlap [lo, hi], and only what's outside of it is kept
+	i := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].hi >= lo })
+	j := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].lo > hi })
+	if i == j {
+		return
+	}
+	var kept []span[T]
+	if first := s.spans[i]; first.lo < lo {
+		kept = append(kept, span[T]{first.lo, lo - 1})
+	}
+	if last := s.spans[j-1]; last.hi > hi {
+		kept = append(kept, span[T]{hi + 1, last.hi})
+	}
+	s.replace(i, j, kept...)
+}
+
+// ContainsRange returns true if every value from lo to hi, inclusively, is in
+// the set
+func (s *IntervalsOf[T]) ContainsRange(lo, hi T) bool {
+	if lo > hi {
+		return true
+	}
+	i := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].hi >= lo })
+	return i < len(s.spans) && s.spans[i].lo <= lo && s.spans[i].hi >= hi
+}
+
+// Gaps returns the values from lo to hi, inclusively, which aren't in the set
+func (s *IntervalsOf[T]) Gaps(lo, hi T) *IntervalsOf[T] {
+	gaps := NewIntervalsOf[T]()
+	if lo > hi {
+		return gaps
+	}
+	i := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].hi >= lo })
+	next := lo
+	for _, span := range s.spans[i:] {
+		if span.lo > hi {
+			break
+		}
+		if span.lo > next {
+			gaps.append(next, span.lo-1)
+		}
+		if span.hi >= hi {
+			return gaps
+		}
+		next = span.hi + 1
+	}
+	gaps.append(next, hi)
+	return gaps
+}
+
+// Sized returns the values of the set as a SizedOf
+func (s *IntervalsOf[T]) Sized() *SizedOf[T] {
+	values := make([]T, 0, s.Len())
+	s.Each(func(value T) {
+		values = append(values, value)
+	})
+	return FromSortedOf(values)
+}
+
+// Intervals returns the values of the set as ranges of consecutive values
+func (s *SizedOf[T]) Intervals() *IntervalsOf[T] {
+	intervals := NewIntervalsOf[T]()
+	values := s.sorted()
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && values[j] == values[j-1]+1 {
+			j++
+		}
+		intervals.append(values[i], values[j-1])
+		i = j
+	}
+	return intervals
+}
+
+// append adds a range after, and not touching, every range of the set
+func (s *IntervalsOf[T]) append(lo, hi T) {
+	s.spans = append(s.spans, span[T]{lo, hi})
+	s.extent += uint64(hi) - uint64(lo)
+}
+
+// replace replaces spans [i, j) with spans, keeping track of the extent
+func (s *IntervalsOf[T]) replace(i, j int, spans ...span[T]) {
+	for _, span := range s.spans[i:j] {
+		s.extent -= uint64(span.hi) - uint64(span.lo)
+	}
+	for _, span := range spans {
+		s.extent += uint64(span.hi) - uint64(span.lo)
+	}
+	tail := len(s.spans) - j
+	if grow := len(spans) - (j - i); grow > 0 {
+		s.spans = append(s.spans, make([]span[T], grow)...)
+	}
+	copy(s.spans[i+len(spans):], s.spans[j:j+tail])
+	copy(s.spans[i:], spans)
+	s.spans = s.spans[:i+len(spans)+tail]
+}
@@ -0,0 +1,142 @@
+package intset
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+var (
+	_ Set     = (*Intervals)(nil)
+	_ Set32   = (*Intervals32)(nil)
+	_ SetRune = (*IntervalsRune)(nil)
+)
+
+func Test_Intervals_MatchesOracle(t *testing.T) {
+	AssertOracle[int](t, NewIntervals(), 1, 100)
+	AssertOracle[uint32](t, NewIntervals32(), 2, 1000)
+	AssertOracle[rune](t, NewIntervalsRune(), 3, 30)
+}
+
+func Test_Intervals_CoalescesRanges(t *testing.T) {
+	s := NewIntervals()
+	s.AddRange(10, 19)
+	s.AddRange(30, 39)
+	s.AddRange(20, 29)
+	AssertRanges(t, s, 10, 39)
+	AssertEqual(t, s.Len(), 30)
+
+	s.AddRange(50, 60)
+	s.AddRange(0, 5)
+	s.AddRange(3, 52)
+	AssertRanges(t, s, 0, 60)
+	s.AddRange(7, 3)
+	AssertEqual(t, s.Len(), 61)
+
+	s.RemoveRange(10, 19)
+	s.RemoveRange(-5, 0)
+	s.RemoveRange(60, 100)
+	AssertRanges(t, s, 1, 9, 20, 59)
+	AssertEqual(t, s.Len(), 49)
+	s.RemoveRange(5, 50)
+	AssertRanges(t, s, 1, 4, 51, 59)
+	s.RemoveRange(0, 100)
+	AssertRanges(t, s)
+	AssertEqual(t, s.Len(), 0)
+}
+
+func Test_Intervals_ContainsRange(t *testing.T) {
+	s := NewIntervals32()
+	s.AddRange(10, 20)
+	s.AddRange(22, 30)
+	AssertTrue(t, s.ContainsRange(10, 20))
+	AssertTrue(t, s.ContainsRange(12, 15))
+	AssertTrue(t, s.ContainsRange(5, 4))
+	AssertFalse(t, s.ContainsRange(9, 12))
+	AssertFalse(t, s.ContainsRange(15, 25))
+	AssertFalse(t, s.ContainsRange(31, 31))
+}
+
+func Test_Intervals_Gaps(t *testing.T) {
+	s := NewIntervals()
+	s.AddRange(10, 20)
+	s.AddRange(30, 40)
+	AssertRanges(t, s.Gaps(0, 50), 0, 9, 21, 29, 41, 50)
+	AssertRanges(t, s.Gaps(15, 35), 21, 29)
+	AssertRanges(t, s.Gaps(12, 18))
+	AssertRanges(t, s.Gaps(25, 26), 25, 26)
+	AssertEqual(t, s.Gaps(0, 50).Len(), 29)
+}
+
+func Test_Intervals_LenSaturates(t *testing.T) {
+	s := NewIntervals()
+	s.AddRange(0, math.MaxInt)
+	AssertEqual(t, s.Len(), math.MaxInt)
+	s.RemoveRange(0, 0)
+	AssertEqual(t, s.Len(), math.MaxInt)
+	s.RemoveRange(1, 1)
+	AssertEqual(t, s.Len(), math.MaxInt-1)
+
+	l := NewIntervalsOf[int64]()
+	l.AddRange(math.MinInt64, math.MaxInt64)
+	AssertEqual(t, l.Len(), math.MaxInt)
+
+	u := NewIntervalsOf[uint64]()
+	u.AddRange(0, math.MaxUint64)
+	AssertEqual(t, u.Len(), math.MaxInt)
+	u.RemoveRange(10, math.MaxUint64)
+	AssertEqual(t, u.Len(), 10)
+	AssertEqual(t, u.Gaps(0, math.MaxUint64).Len(), math.MaxInt)
+}
+
+func Test_Intervals_Extremes(t *testing.T) {
+	s := NewIntervalsOf[int8]()
+	s.AddRange(-128, -1)
+	s.AddRange(0, 127)
+	AssertRanges(t, s, -128, 127)
+	AssertEqual(t, s.Len(), 256)
+	AssertEqual(t, s.Gaps(-128, 127).Len(), 0)
+	s.RemoveRange(127, 127)
+	s.RemoveRange(-128, -128)
+	AssertRanges(t, s, -127, 126)
+	AssertRanges(t, s.Gaps(-128, 127), -128, -128, 127, 127)
+
+	u := NewIntervalsOf[uint8]()
+	u.AddRange(255, 255)
+	u.AddRange(0, 0)
+	AssertRanges(t, u, 0, 0, 255, 255)
+}
+
+func Test_Intervals_ConvertsToAndFromSized(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	sized := NewSized(100)
+	for i := 0; i < 300; i++ {
+		sized.Set(r.Intn(500) - 250)
+	}
+	intervals := sized.Intervals()
+	AssertEqual(t, intervals.Len(), sized.Len())
+	AssertTrue(t, Equal(intervals, sized))
+	AssertTrue(t, Equal(intervals.Sized(), sized))
+	AssertTrue(t, slices.IsSorted(slices.Collect(intervals.All())))
+
+	previous, first := 0, true
+	for lo, hi := range intervals.Ranges() {
+		AssertTrue(t, lo <= hi)
+		AssertTrue(t, first || lo > previous+1)
+		previous, first = hi, false
+	}
+}
+
+// AssertRanges checks that s holds exactly the ranges given as lo, hi pairs
+func AssertRanges[T Integer](t *testing.T, s *IntervalsOf[T], bounds ...T) {
+	t.Helper()
+	var actual []T
+	for lo, hi := range s.Ranges() {
+		actual = append(actual, lo, hi)
+	}
+	if slices.Equal(actual, bounds) == false {
+		t.Errorf("\nexpected: '%v'\nto equal: '%v'", actual, bounds)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,226 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// All returns an iterator over the set items, in no particular order. Unlike
+// Each, iteration can be stopped early.
+func (s *SizedOf[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, bucket := range s.buckets {
+			for _, value := range bucket {
+				if yield(value) == false {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the set items in ascending order
+func (s *SizedOf[T]) Sorted() iter.Seq[T] {
+	return merge(s.buckets, false)
+}
+
+// Backward returns an iterator over the set items in descending order
+func (s *SizedOf[T]) Backward() iter.Seq[T] {
+	return merge(s.buckets, true)
+}
+
+// All returns an iterator over the set items, in no particular order
+func (f *FrozenSizedOf[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range f.values {
+			if yield(value) == false {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the set items in ascending order
+func (f *FrozenSizedOf[T]) Sorted() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		merge(f.buckets(), false)(yield)
+	}
+}
+
+// Backward returns an iterator over the set items in descending order
+func (f *FrozenSizedOf[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		merge(f.buckets(), true)(yield)
+	}
+}
+
+// buckets returns a view of each bucket
+func (f *FrozenSizedOf[T]) buckets() [][]T {
+	buckets := make([][]T, len(f.offsets)-1)
+	for i := range buckets {
+		buckets[i] = f.values[f.offsets[i]:f.offsets[i+1]]
+	}
+	return buckets
+}
+
+// All returns an iterator over a consistent snapshot of the set items, in no
+// particular order
+func (c *ConcurrentOf[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range c.snapshot() {
+			if yield(value) == false {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over a snapshot of the set items, in no particular order
+func (a *AtomicOf[T]) All() iter.Seq[T] {
+	return a.set.Load().All()
+}
+
+// merge yields the values of sorted buckets in ascending order, or descending
+// when reverse is true, using a k-way merge
+func merge[T Integer](buckets [][]T, reverse bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h := mergeHeap[T]{reverse: reverse}
+		for _, bucket := range buckets {
+			if len(bucket) > 0 {
+				h.items = append(h.items, bucket)
+			}
+		}
+		for i := len(h.items)/2 - 1; i >= 0; i-- {
+			h.down(i)
+		}
+		for len(h.items) > 0 {
+			if yield(h.head(0)) == false {
+				return
+			}
+			top := h.items[0]
+			if reverse {
+				top = top[:len(top)-1]
+			} else {
+				top = top[1:]
+			}
+			if len(top) == 0 {
+				last := len(h.items) - 1
+				top = h.items[last]
+				h.items = h.items[:last]
+				if last == 0 {
+					return
+				}
+			}
+			h.items[0] = top
+			h.down(0)
+		}
+	}
+}
+
+// mergeHeap is a binary heap of the unconsumed part of each bucket, ordered by
+// the next value each will yield
+type mergeHeap[T Integer] struct {
+	items   [][]T
+	reverse bool
+}
+
+func (h *mergeHeap[T]) head(i int) T {
+	bucket := h.items[i]
+	if h.reverse {
+		return bucket[len(bucket)-1]
+	}
+	return bucket[0]
+}
+
+func (h *mergeHeap[T]) less(i, j int) bool {
+	if h.reverse {
+		return h.head(i) > h.head(j)
+	}
+	return h.head(i) < h.head(j)
+}
+
+func (h *mergeHeap[T]) down(i int) {
+	n := len(h.items)
+	for {
+		smallest, left, right := i, 2*i+1, 2*i+2
+		if left < n && h.less(left, smallest) {
+			smallest = left
+		}
+		if right < n && h.less(right, smallest) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		h.items[i], h.items[smallest] = h.items[smallest], h.items[i]
+		i = smallest
+	}
+}
+
+// All returns an iterator over the set items in ascending order
+func (r *RoaringOf[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range r.containers {
+			key := r.keys[i]
+			if r.containers[i].each(func(low uint16) bool { return yield(r.join(key, low)) }) == false {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the set items in ascending order
+func (b *BitsetOf[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i, word := range b.words {
+			for word != 0 {
+				if yield(T(i<<6|bits.TrailingZeros64(word))) == false {
+					return
+				}
+				word &= word - 1
+			}
+		}
+	}
+}
+
+// All returns an iterator over the set items in ascending order
+func (s *IntervalsOf[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, span := range s.spans {
+			for value := span.lo; ; value++ {
+				if yield(value) == false {
+					return
+				}
+				if value == span.hi {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Ranges returns an iterator over the ranges of the set, in ascending order,
+// yielding the first and last value of each
+func (s *IntervalsOf[T]) Ranges() iter.Seq2[T, T] {
+	return func(yield func(T, T) bool) {
+		for _, span := range s.spans {
+			if yield(span.lo, span.hi) == false {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the set items in ascending order
+func (o *OrderedOf[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, chunk := range o.chunks {
+			for _, value := range chunk {
+				if yield(value) == false {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package intset
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func Test_All_StopsEarly(t *testing.T) {
+	s := NewSized(100)
+	for i := 0; i < 100; i++ {
+		s.Set(i)
+	}
+	for _, set := range []Set{s, s.Freeze(), NewConcurrent(10), NewAtomic(10)} {
+		count := 0
+		for range set.All() {
+			count++
+			if count == 10 {
+				break
+			}
+		}
+		AssertEqual(t, count, min(10, set.Len()))
+	}
+}
+
+func Test_All_YieldsEveryValue(t *testing.T) {
+	s := NewConcurrent32(100)
+	for i := uint32(0); i < 100; i++ {
+		s.Set(i * 3)
+	}
+	seen := make(map[uint32]struct{})
+	for value := range s.All() {
+		seen[value] = struct{}{}
+	}
+	AssertEqual(t, len(seen), 100)
+	AssertTrue(t, s.Exists(99*3))
+}
+
+func Test_Sorted_IsAscending(t *testing.T) {
+	s := NewSizedConfig(100, NewConfig().Hasher(Murmur3Hash))
+	expected := make([]int, 0, 1000)
+	r := rand.New(rand.NewSource(1))
+	for len(expected) < 1000 {
+		value := r.Intn(100000) - 50000
+		if s.Exists(value) == false {
+			s.Set(value)
+			expected = append(expected, value)
+		}
+	}
+	sort.Ints(expected)
+
+	assertSequence(t, collect(s.Sorted()), expected)
+	assertSequence(t, collect(s.Freeze().Sorted()), expected)
+
+	sort.Sort(sort.Reverse(sort.IntSlice(expected)))
+	assertSequence(t, collect(s.Backward()), expected)
+	assertSequence(t, collect(s.Freeze().Backward()), expected)
+}
+
+func Test_Sorted_RuneAndSized32(t *testing.T) {
+	r := NewRune(10)
+	for _, value := range "hello world" {
+		r.Set(value)
+	}
+	AssertEqual(t, string(collect(r.Sorted())), " dehlorw")
+	AssertEqual(t, string(collect(r.Backward())), "wrolhed ")
+
+	s := NewSized32(10)
+	s.Set(1 << 31)
+	s.Set(7)
+	assertSequence(t, collect(s.Sorted()), []uint32{7, 1 << 31})
+	assertSequence(t, collect(NewSized32(10).Sorted()), nil)
+}
+
+func Test_Sorted_StopsEarly(t *testing.T) {
+	s := NewSized(1000)
+	for i := 999; i >= 0; i-- {
+		s.Set(i)
+	}
+	var first []int
+	for value := range s.Sorted() {
+		first = append(first, value)
+		if len(first) == 3 {
+			break
+		}
+	}
+	assertSequence(t, first, []int{0, 1, 2})
+}
+
+func collect[T Integer](seq func(func(T) bool)) []T {
+	var values []T
+	for value := range seq {
+		values = append(values, value)
+	}
+	return values
+}
+
+func assertSequence[T Integer](t *testing.T, actual []T, expected []T) {
+	t.Helper()
+	AssertEqual(t, len(actual), len(expected))
+	for i := range expected {
+		AssertEqual(t, actual[i], expected[i])
+	}
+}
+
+func Benchmark_SizedSorted(b *testing.B) {
+	s := NewSized(100000)
+	for i := 0; i < 100000; i++ {
+		s.Set(rand.Int())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range s.Sorted() {
+		}
+	}
+}
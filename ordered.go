@@ -0,0 +1,254 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import "sort"
+
+// orderedChunk is the most values a chunk of an OrderedOf holds before it's
+// split in two
+const orderedChunk = 512
+
+// OrderedOf is a set which keeps its values in ascending order, as a list of
+// sorted chunks. Unlike Sized, which scatters values across buckets, it can
+// answer order queries: the smallest value at least x, the number of values
+// in a range, the k-th smallest value, ...
+type OrderedOf[T Integer] struct {
+	chunks [][]T
+	// maxes holds the last value of each chunk
+	maxes []T
+	// prefix holds the number of values before each chunk. It's rebuilt
+	// lazily, and is nil when the set has been modified since.
+	prefix []int
+	length int
+}
+
+// Ordered is an ordered int set
+type Ordered = OrderedOf[int]
+
+// Ordered32 is an ordered uint32 set
+type Ordered32 = OrderedOf[uint32]
+
+// OrderedRune is an ordered rune set
+type OrderedRune = OrderedOf[rune]
+
+// NewOrderedOf creates an empty ordered set
+func NewOrderedOf[T Integer]() *OrderedOf[T] {
+	return &OrderedOf[T]{}
+}
+
+// NewOrdered creates an empty ordered int set
+func NewOrdered() *Ordered {
+	return NewOrderedOf[int]()
+}
+
+// NewOrdered32 creates an empty ordered uint32 set
+func NewOrdered32() *Ordered32 {
+	return NewOrderedOf[uint32]()
+}
+
+// NewOrderedRune creates an empty ordered rune set
+func NewOrderedRune() *OrderedRune {
+	return NewOrderedOf[rune]()
+}
+
+// Set adds a value to the set
+func (o *OrderedOf[T]) Set(value T) {
+	if o.length == 0 {
+		o.chunks = [][]T{{value}}
+		o.maxes = []T{value}
+		o.prefix = nil
+		o.length = 1
+		return
+	}
+	i := o.locate(value)
+	if i == len(o.chunks) {
+		i--
+	}
+	chunk := o.chunks[i]
+	j := search(chunk, value)
+	if j < len(chunk) && chunk[j] == value {
+		return
+	}
+	chunk = append(chunk, 0)
+	copy(chunk[j+1:], chunk[j:])
+	chunk[j] = value
+	o.chunks[i] = chunk
+	o.maxes[i] = chunk[len(chunk)-1]
+	o.prefix = nil
+	o.length++
+
+	if len(chunk) > orderedChunk {
+		half := len(chunk) / 2
+		upper := append(make([]T, 0, orderedChunk), chunk[half:]...)
+		o.chunks[i] = chunk[:half]
+		o.maxes[i] = chunk[half-1]
+		o.chunks = append(o.chunks, nil)
+		copy(o.chunks[i+2:], o.chunks[i+1:])
+		o.chunks[i+1] = upper
+		o.maxes = append(o.maxes, 0)
+		copy(o.maxes[i+2:], o.maxes[i+1:])
+		o.maxes[i+1] = upper[len(upper)-1]
+	}
+}
+
+// Remove returns true if the value existed in the set before being removed
+func (o *OrderedOf[T]) Remove(value T) bool {
+	i := o.locate(value)
+	if i == len(o.chunks) {
+		return false
+	}
+	chunk := o.chunks[i]
+	j := search(chunk, value)
+	if chunk[j] != value {
+		return false
+	}
+	o.prefix = nil
+	o.length--
+	if len(chunk) == 1 {
+		o.chunks = append(o.chunks[:i], o.chunks[i+1:]...)
+		o.maxes = append(o.maxes[:i], o.maxes[i+1:]...)
+		return true
+	}
+	chunk = append(chunk[:j], chunk[j+1:]...)
+	o.chunks[i] = chunk
+	o.maxes[i] = chunk[len(chunk)-1]
+	return true
+}
+
+// Exists returns true if the value exists in the set
+func (o *OrderedOf[T]) Exists(value T) bool {
+	i := o.locate(value)
+	if i == len(o.chunks) {
+		return false
+	}
+	chunk := o.chunks[i]
+	return chunk[search(chunk, value)] == value
+}
+
+// Len returns the total number of elements in the set
+func (o *OrderedOf[T]) Len() int {
+	return o.length
+}
+
+// Each iterates through the set items, in ascending order, and applies
+// function f to each set item
+func (o *OrderedOf[T]) Each(f func(value T)) {
+	for _, chunk := range o.chunks {
+		for _, value := range chunk {
+			f(value)
+		}
+	}
+}
+
+// Min returns the smallest value of the set, or false if it's empty
+func (o *OrderedOf[T]) Min() (T, bool) {
+	if o.length == 0 {
+		return 0, false
+	}
+	return o.chunks[0][0], true
+}
+
+// Max returns the largest value of the set, or false if it's empty
+func (o *OrderedOf[T]) Max() (T, bool) {
+	if o.length == 0 {
+		return 0, false
+	}
+	return o.maxes[len(o.maxes)-1], true
+}
+
+// Ceiling returns the smallest value of the set which is at least value, or
+// false if there's none
+func (o *OrderedOf[T]) Ceiling(value T) (T, bool) {
+	i := o.locate(value)
+	if i == len(o.chunks) {
+		return 0, false
+	}
+	chunk := o.chunks[i]
+	return chunk[search(chunk, value)], true
+}
+
+// Floor returns the largest value of the set which is at most value, or false
+// if there's none
+func (o *OrderedOf[T]) Floor(value T) (T, bool) {
+	i := o.locate(value)
+	if i < len(o.chunks) {
+		chunk := o.chunks[i]
+		j := search(chunk, value)
+		if chunk[j] == value {
+			return value, true
+		}
+		if j > 0 {
+			return chunk[j-1], true
+		}
+	}
+	if i == 0 {
+		return 0, false
+	}
+	return o.maxes[i-1], true
+}
+
+// Rank returns the number of values of the set which are less than value
+func (o *OrderedOf[T]) Rank(value T) int {
+	i := o.locate(value)
+	if i == len(o.chunks) {
+		return o.length
+	}
+	return o.offsets()[i] + search(o.chunks[i], value)
+}
+
+// Select returns the k-th smallest value of the set, counting from 0, or
+// false if k isn't in [0, Len())
+func (o *OrderedOf[T]) Select(k int) (T, bool) {
+	if k < 0 || k >= o.length {
+		return 0, false
+	}
+	prefix := o.offsets()
+	i := sort.Search(len(prefix), func(i int) bool { return prefix[i] > k }) - 1
+	return o.chunks[i][k-prefix[i]], true
+}
+
+// CountRange returns the number of values of the set in [lo, hi)
+func (o *OrderedOf[T]) CountRange(lo, hi T) int {
+	if lo >= hi {
+		return 0
+	}
+	return o.Rank(hi) - o.Rank(lo)
+}
+
+// EachRange applies function f to each value of the set in [lo, hi), in
+// ascending order
+func (o *OrderedOf[T]) EachRange(lo, hi T, f func(value T)) {
+	if lo >= hi {
+		return
+	}
+	i := o.locate(lo)
+	if i == len(o.chunks) {
+		return
+	}
+	for j := search(o.chunks[i], lo); i < len(o.chunks); i, j = i+1, 0 {
+		for _, value := range o.chunks[i][j:] {
+			if value >= hi {
+				return
+			}
+			f(value)
+		}
+	}
+}
+
+// locate returns the index of the first chunk whose last value is at least
+// value, which is the only chunk value can be in
+func (o *OrderedOf[T]) locate(value T) int {
+	return search(o.maxes, value)
+}
+
+// offsets returns the number of values before each chunk
+func (o *OrderedOf[T]) offsets() []int {
+	if o.prefix == nil {
+		o.prefix = make([]int, len(o.chunks))
+		count := 0
+		for i, chunk := range o.chunks {
+			o.prefix[i] = count
+			count += len(chunk)
+		}
+	}
+	return o.prefix
+}
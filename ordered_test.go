@@ -0,0 +1,122 @@
+package intset
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+var (
+	_ Set     = (*Ordered)(nil)
+	_ Set32   = (*Ordered32)(nil)
+	_ SetRune = (*OrderedRune)(nil)
+)
+
+func Test_Ordered_MatchesOracle(t *testing.T) {
+	AssertOracle[int](t, NewOrdered(), 1, 5000)
+	AssertOracle[uint32](t, NewOrdered32(), 2, 100)
+	AssertOracle[rune](t, NewOrderedRune(), 3, 100000)
+}
+
+func Test_Ordered_Empty(t *testing.T) {
+	o := NewOrdered()
+	_, ok := o.Min()
+	AssertFalse(t, ok)
+	_, ok = o.Max()
+	AssertFalse(t, ok)
+	_, ok = o.Ceiling(0)
+	AssertFalse(t, ok)
+	_, ok = o.Floor(0)
+	AssertFalse(t, ok)
+	_, ok = o.Select(0)
+	AssertFalse(t, ok)
+	AssertEqual(t, o.Rank(5), 0)
+	AssertEqual(t, o.CountRange(0, 10), 0)
+	AssertFalse(t, o.Exists(0))
+	AssertFalse(t, o.Remove(0))
+	o.EachRange(0, 10, func(value int) { t.FailNow() })
+}
+
+func Test_Ordered_Queries(t *testing.T) {
+	r := rand.New(rand.NewSource(24))
+	o := NewOrdered()
+	var values []int
+	for len(values) < 5000 {
+		value := r.Intn(100000) - 50000
+		if o.Exists(value) == false {
+			values = append(values, value)
+		}
+		o.Set(value)
+		if r.Intn(10) == 0 {
+			value := values[r.Intn(len(values))]
+			AssertTrue(t, o.Remove(value))
+			values = slices.DeleteFunc(values, func(v int) bool { return v == value })
+		}
+	}
+	sort.Ints(values)
+	AssertTrue(t, slices.Equal(slices.Collect(o.All()), values))
+	AssertTrue(t, len(o.chunks) > 10)
+
+	smallest, _ := o.Min()
+	largest, _ := o.Max()
+	AssertEqual(t, smallest, values[0])
+	AssertEqual(t, largest, values[len(values)-1])
+	for k, value := range values {
+		selected, ok := o.Select(k)
+		AssertTrue(t, ok)
+		AssertEqual(t, selected, value)
+		AssertEqual(t, o.Rank(value), k)
+	}
+	_, ok := o.Select(len(values))
+	AssertFalse(t, ok)
+
+	for i := 0; i < 1000; i++ {
+		lo := r.Intn(110000) - 55000
+		hi := lo + r.Intn(1000)
+		first := sort.SearchInts(values, lo)
+		last := sort.SearchInts(values, hi)
+
+		AssertEqual(t, o.Rank(lo), first)
+		AssertEqual(t, o.CountRange(lo, hi), last-first)
+		var in []int
+		o.EachRange(lo, hi, func(value int) {
+			in = append(in, value)
+		})
+		AssertTrue(t, slices.Equal(in, values[first:last]))
+
+		ceiling, ok := o.Ceiling(lo)
+		AssertEqual(t, ok, first < len(values))
+		if ok {
+			AssertEqual(t, ceiling, values[first])
+		}
+		floor, ok := o.Floor(lo)
+		expected := sort.SearchInts(values, lo+1) - 1
+		AssertEqual(t, ok, expected >= 0)
+		if ok {
+			AssertEqual(t, floor, values[expected])
+		}
+	}
+	AssertEqual(t, o.CountRange(10, 10), 0)
+	AssertEqual(t, o.CountRange(10, -10), 0)
+}
+
+func Test_Ordered_WorksWithIntersect(t *testing.T) {
+	o := NewOrdered()
+	s := NewSized(100)
+	for i := 0; i < 100; i++ {
+		o.Set(i)
+		s.Set(i * 2)
+	}
+	AssertEqual(t, Intersect(o, s).Len(), 50)
+	AssertEqual(t, Union(o, s).Len(), 150)
+}
+
+func Benchmark_OrderedPopulate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		o := NewOrdered()
+		for j := 0; j < 100000; j++ {
+			o.Set(rand.Int())
+		}
+	}
+}
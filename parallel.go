@@ -0,0 +1,253 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// maxCollectWorkers bounds the workers of parallelCollect
+const maxCollectWorkers = 256
+
+// ParallelIntersectOf returns the intersection of sets, splitting the work
+// across workers goroutines (0 for GOMAXPROCS). Sets which IntersectOf
+// combines bucket by bucket are merged range by range. Otherwise, the values
+// of the smallest set are split across workers, which look them up in the
+// other sets.
+func ParallelIntersectOf[T Integer](workers int, sets ...SetOf[T]) *SizedOf[T] {
+	if len(sets) == 0 {
+		return NewSizedOf[T](0)
+	}
+	if sized, ok := allSized(sets); ok {
+		driver := intersectDriver(sized)
+		return driver.parallelFill(workers, func(lo, hi int, offsets []int) []T {
+			values := make([]T, 0, driver.rangeLen(lo, hi))
+			return intersectBuckets(sized, driver, lo, hi, values, offsets)
+		})
+	}
+
+	d := 0
+	for i, set := range sets {
+		if set.Len() < sets[d].Len() {
+			d = i
+		}
+	}
+	// a SizedOf's buckets are already sorted, so its layout is kept
+	if driver, ok := sets[d].(*SizedOf[T]); ok {
+		return driver.parallelFill(workers, func(lo, hi int, offsets []int) []T {
+			values := make([]T, 0, driver.rangeLen(lo, hi))
+			for i := lo; i < hi; i++ {
+				for _, value := range driver.buckets[i] {
+					if inAll(sets, d, value) {
+						values = append(values, value)
+					}
+				}
+				offsets[i+1] = len(values)
+			}
+			return values
+		})
+	}
+
+	values := make([]T, 0, sets[d].Len())
+	sets[d].Each(func(value T) {
+		values = append(values, value)
+	})
+	workers = min(parallelWorkers(workers, len(values)), maxCollectWorkers)
+	tasks := make([]func(emit func(value T)), workers)
+	for w := range tasks {
+		part := values[len(values)*w/workers : len(values)*(w+1)/workers]
+		tasks[w] = func(emit func(value T)) {
+			for _, value := range part {
+				if inAll(sets, d, value) {
+					emit(value)
+				}
+			}
+		}
+	}
+	return parallelCollect(workers, len(values), tasks)
+}
+
+// ParallelUnionOf returns the union of sets, splitting the work across
+// workers goroutines (0 for GOMAXPROCS). Sets which UnionOf combines bucket
+// by bucket are merged range by range. Otherwise, workers read the sets,
+// SizedOf ones a range of buckets at a time, and then sort the values of a
+// range of buckets of the result each.
+func ParallelUnionOf[T Integer](workers int, sets ...SetOf[T]) *SizedOf[T] {
+	if len(sets) == 0 {
+		return NewSizedOf[T](0)
+	}
+	if sized, ok := allSized(sets); ok {
+		widest := unionWidest(sized)
+		return widest.parallelFill(workers, func(lo, hi int, offsets []int) []T {
+			size := 0
+			for _, set := range sized {
+				size += set.rangeLen(lo, hi)
+			}
+			return unionBuckets(sized, widest.mask, lo, hi, make([]T, 0, size), offsets)
+		})
+	}
+
+	total := 0
+	var tasks []func(emit func(value T))
+	for _, set := range sets {
+		total += set.Len()
+		s, ok := set.(*SizedOf[T])
+		if ok == false {
+			tasks = append(tasks, set.Each)
+			continue
+		}
+		split := parallelWorkers(workers, len(s.buckets))
+		for w := 0; w < split; w++ {
+			buckets := s.buckets[len(s.buckets)*w/split : len(s.buckets)*(w+1)/split]
+			tasks = append(tasks, func(emit func(value T)) {
+				for _, bucket := range buckets {
+					for _, value := range bucket {
+						emit(value)
+					}
+				}
+			})
+		}
+	}
+	return parallelCollect(workers, total, tasks)
+}
+
+// parallelCollect returns a set, with default configuration and sized for
+// size values, holding the values emitted by tasks. Workers take tasks in
+// turn and scatter the values by the range of buckets they belong in; each
+// worker then sorts and deduplicates the buckets of its own range.
+func parallelCollect[T Integer](workers, size int, tasks []func(emit func(value T))) *SizedOf[T] {
+	s := NewSizedOf[T](size)
+	count := len(s.buckets)
+	// every worker keeps a list per worker, so their number is bounded
+	workers = min(parallelWorkers(workers, count), maxCollectWorkers)
+	// scattered[w][r] holds the values worker w read for the range of worker r
+	scattered := make([][][]T, workers)
+	var next atomic.Int64
+	parallel(workers, workers, func(w, _, _ int) {
+		ranges := make([][]T, workers)
+		for t := int(next.Add(1)) - 1; t < len(tasks); t = int(next.Add(1)) - 1 {
+			tasks[t](func(value T) {
+				r := rangeOf(int(s.bucket(value)), workers, count)
+				ranges[r] = append(ranges[r], value)
+			})
+		}
+		scattered[w] = ranges
+	})
+
+	return s.parallelFill(workers, func(lo, hi int, offsets []int) []T {
+		w := rangeOf(lo, workers, count)
+		// group the range's values by bucket, then sort and deduplicate each
+		starts := make([]int, hi-lo+1)
+		for _, ranges := range scattered {
+			for _, value := range ranges[w] {
+				starts[int(s.bucket(value))-lo+1]++
+			}
+		}
+		for i := 1; i < len(starts); i++ {
+			starts[i] += starts[i-1]
+		}
+		grouped := make([]T, starts[hi-lo])
+		next := append([]int(nil), starts[:hi-lo]...)
+		for _, ranges := range scattered {
+			for _, value := range ranges[w] {
+				index := int(s.bucket(value)) - lo
+				grouped[next[index]] = value
+				next[index]++
+			}
+		}
+		n := 0
+		for i := lo; i < hi; i++ {
+			bucket := grouped[starts[i-lo]:starts[i-lo+1]]
+			slices.Sort(bucket)
+			for j, value := range bucket {
+				if j == 0 || value != grouped[n-1] {
+					grouped[n] = value
+					n++
+				}
+			}
+			offsets[i+1] = n
+		}
+		return grouped[:n]
+	})
+}
+
+// parallelFill returns a set configured like s, splitting its buckets into one
+// contiguous range per worker. build is called for each range from its own
+// goroutine and returns the values of buckets [lo, hi), setting offsets[i+1]
+// to the end of bucket i within them. The buckets then slice those values
+// where they are, rather than them being copied into a single array.
+func (s *SizedOf[T]) parallelFill(workers int, build func(lo, hi int, offsets []int) []T) *SizedOf[T] {
+	f := s.like()
+	workers = parallelWorkers(workers, len(f.buckets))
+	offsets := make([]int, len(f.buckets)+1)
+	lengths := make([]int, workers)
+	parallel(workers, len(f.buckets), func(w, lo, hi int) {
+		values := build(lo, hi, offsets)
+		start := 0
+		for i := lo; i < hi; i++ {
+			end := offsets[i+1]
+			f.buckets[i] = values[start:end:end]
+			start = end
+		}
+		lengths[w] = len(values)
+	})
+	for _, length := range lengths {
+		f.length += length
+	}
+	return f
+}
+
+// rangeLen returns how many values of the set can be in buckets [lo, hi) of a
+// set with at least as many buckets
+func (s *SizedOf[T]) rangeLen(lo, hi int) int {
+	if uint64(hi-lo) > s.mask {
+		return s.length
+	}
+	n := 0
+	for j := lo; j < hi; j++ {
+		n += len(s.buckets[uint64(j)&s.mask])
+	}
+	return n
+}
+
+// parallelWorkers returns how many workers to split count items across, 0
+// workers meaning GOMAXPROCS
+func parallelWorkers(workers, count int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return max(1, min(workers, count))
+}
+
+// rangeOf returns the worker whose range, as split by parallel, holds item i
+func rangeOf(i, workers, count int) int {
+	return ((i+1)*workers - 1) / count
+}
+
+// parallel splits count items into one contiguous range per worker and calls
+// f for each range from its own goroutine, returning once they're all done
+func parallel(workers, count int, f func(w, lo, hi int)) {
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			f(w, count*w/workers, count*(w+1)/workers)
+		}(w)
+	}
+	wg.Wait()
+}
+
+// ParallelIntersect returns the intersection of sets, splitting the work
+// across workers goroutines (0 for GOMAXPROCS)
+func ParallelIntersect(workers int, sets ...Set) *Sized {
+	return ParallelIntersectOf(workers, sets...)
+}
+
+// ParallelUnion returns the union of sets, splitting the work across workers
+// goroutines (0 for GOMAXPROCS)
+func ParallelUnion(workers int, sets ...Set) *Sized {
+	return ParallelUnionOf(workers, sets...)
+}
@@ -0,0 +1,101 @@
+package intset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_Parallel_MatchesSequential(t *testing.T) {
+	sets := alignedSets()
+	intersection, union := Intersect(sets...), Union(sets...)
+	for _, workers := range []int{0, 1, 3, 8, 1 << 20} {
+		s := ParallelIntersect(workers, sets...)
+		AssertSorted(t, s)
+		AssertTrue(t, Equal(s, intersection))
+
+		s = ParallelUnion(workers, sets...)
+		AssertSorted(t, s)
+		AssertTrue(t, Equal(s, union))
+	}
+}
+
+func Test_Parallel_MixedSets(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	for round := 0; round < 5; round++ {
+		hashed := NewSizedConfig(64, NewConfig().Hasher(FibonacciHash))
+		plain, frozen, ordered := NewSized(256), NewSized(16), NewOrdered()
+		for i := 0; i < 2000; i++ {
+			hashed.Set(r.Intn(3000))
+			plain.Set(r.Intn(3000) - 100)
+			frozen.Set(r.Intn(3000))
+			ordered.Set(r.Intn(3000))
+		}
+		for _, sets := range []Sets{
+			{hashed, plain},
+			{plain, hashed},
+			{plain, frozen.Freeze()},
+			{ordered, hashed, frozen.Freeze()},
+			{ordered, ordered},
+		} {
+			intersection, union := Intersect(sets...), Union(sets...)
+			for _, workers := range []int{0, 1, 3, 8, 1 << 20} {
+				s := ParallelIntersect(workers, sets...)
+				AssertSorted(t, s)
+				AssertTrue(t, Equal(s, intersection))
+
+				s = ParallelUnion(workers, sets...)
+				AssertSorted(t, s)
+				AssertTrue(t, Equal(s, union))
+			}
+		}
+	}
+	AssertEqual(t, ParallelIntersect(4, NewOrdered(), NewSized(10)).Len(), 0)
+	AssertEqual(t, ParallelUnion(4, NewOrdered(), NewOrdered()).Len(), 0)
+	AssertEqual(t, ParallelIntersect(4).Len(), 0)
+	AssertEqual(t, ParallelUnion(4).Len(), 0)
+}
+
+func Test_Parallel_RangeOf(t *testing.T) {
+	for _, count := range []int{1, 7, 64, 1000} {
+		for workers := 1; workers <= count && workers < 20; workers++ {
+			parallel(workers, count, func(w, lo, hi int) {
+				for i := lo; i < hi; i++ {
+					if rangeOf(i, workers, count) != w {
+						t.Errorf("item %d of %d isn't in range %d of %d", i, count, w, workers)
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_Parallel_OtherTypes(t *testing.T) {
+	a, b := NewSized32(1000), NewSized32(100)
+	r1, r2 := NewRune(1000), NewRune(100)
+	for i := 0; i < 1000; i++ {
+		a.Set(uint32(i))
+		b.Set(uint32(i * 3))
+		r1.Set(rune(i))
+		r2.Set(rune(i * 3))
+	}
+	AssertEqual(t, ParallelIntersect32(4, a, b).Len(), 334)
+	AssertEqual(t, ParallelUnion32(4, a, b).Len(), 1666)
+	AssertEqual(t, ParallelIntersectRune(4, r1, r2).Len(), 334)
+	AssertEqual(t, ParallelUnionRune(4, r1, r2).Len(), 1666)
+}
+
+func Benchmark_ParallelIntersect(b *testing.B) {
+	sets := alignedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParallelIntersect(0, sets...)
+	}
+}
+
+func Benchmark_ParallelUnion(b *testing.B) {
+	sets := alignedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParallelUnion(0, sets...)
+	}
+}
@@ -0,0 +1,220 @@
+// Package intset provides a specialized set for integers or runes
+package intset
+
+// RoaringOf is a set which partitions values on their high bits, keeping the
+// low 16 bits of the values which share the same high bits in a container: a
+// sorted array when there are few of them, a bitmap when there are many, or
+// runs of consecutive values once Optimize finds those smaller. It suits
+// clustered values, such as contiguous ranges of IDs, which Sized stores one
+// by one.
+type RoaringOf[T Integer] struct {
+	keys       []uint64
+	containers []container
+	length     int
+}
+
+// Roaring is an int set of containers
+type Roaring = RoaringOf[int]
+
+// Roaring32 is a uint32 set of containers
+type Roaring32 = RoaringOf[uint32]
+
+// RoaringRune is a rune set of containers
+type RoaringRune = RoaringOf[rune]
+
+// NewRoaringOf creates an empty set of containers
+func NewRoaringOf[T Integer]() *RoaringOf[T] {
+	return &RoaringOf[T]{}
+}
+
+// NewRoaring creates an empty int set of containers
+func NewRoaring() *Roaring {
+	return NewRoaringOf[int]()
+}
+
+// NewRoaring32 creates an empty uint32 set of containers
+func NewRoaring32() *Roaring32 {
+	return NewRoaringOf[uint32]()
+}
+
+// NewRoaringRune creates an empty rune set of containers
+func NewRoaringRune() *RoaringRune {
+	return NewRoaringOf[rune]()
+}
+
+// Set adds a value to the set
+func (r *RoaringOf[T]) Set(value T) {
+	key, low := r.split(value)
+	i, ok := r.find(key)
+	if ok == false {
+		r.keys = append(r.keys, 0)
+		copy(r.keys[i+1:], r.keys[i:])
+		r.keys[i] = key
+		r.containers = append(r.containers, container{})
+		copy(r.containers[i+1:], r.containers[i:])
+		r.containers[i] = container{kind: arrayContainer}
+	}
+	if r.containers[i].add(low) {
+		r.length++
+	}
+}
+
+// Remove returns true if the value existed in the set before being removed
+func (r *RoaringOf[T]) Remove(value T) bool {
+	key, low := r.split(value)
+	i, ok := r.find(key)
+	if ok == false || r.containers[i].remove(low) == false {
+		return false
+	}
+	r.length--
+	if r.containers[i].length == 0 {
+		r.keys = append(r.keys[:i], r.keys[i+1:]...)
+		r.containers = append(r.containers[:i], r.containers[i+1:]...)
+	}
+	return true
+}
+
+// Exists returns true if the value exists in the set
+func (r *RoaringOf[T]) Exists(value T) bool {
+	key, low := r.split(value)
+	i, ok := r.find(key)
+	return ok && r.containers[i].contains(low)
+}
+
+// Len returns the total number of elements in the set
+func (r *RoaringOf[T]) Len() int {
+	return r.length
+}
+
+// Each iterates through the set items, in ascending order, and applies
+// function f to each set item
+func (r *RoaringOf[T]) Each(f func(value T)) {
+	for i := range r.containers {
+		key := r.keys[i]
+		r.containers[i].each(func(low uint16) bool {
+			f(r.join(key, low))
+			return true
+		})
+	}
+}
+
+// Optimize converts each container to whichever of an array, a bitmap or runs
+// of consecutive values takes the least memory. Call it once the set is built.
+func (r *RoaringOf[T]) Optimize() {
+	for i := range r.containers {
+		r.containers[i].optimize()
+	}
+}
+
+// And returns the values in both r and other
+func (r *RoaringOf[T]) And(other *RoaringOf[T]) *RoaringOf[T] {
+	result := NewRoaringOf[T]()
+	i, j := 0, 0
+	for i < len(r.keys) && j < len(other.keys) {
+		switch {
+		case r.keys[i] < other.keys[j]:
+			i++
+		case r.keys[i] > other.keys[j]:
+			j++
+		default:
+			result.append(r.keys[i], andContainers(&r.containers[i], &other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Or returns the values in either r or other
+func (r *RoaringOf[T]) Or(other *RoaringOf[T]) *RoaringOf[T] {
+	return r.combine(other, orContainers, true)
+}
+
+// AndNot returns the values of r which aren't in other
+func (r *RoaringOf[T]) AndNot(other *RoaringOf[T]) *RoaringOf[T] {
+	return r.combine(other, andNotContainers, false)
+}
+
+// Xor returns the values in either r or other, but not both
+func (r *RoaringOf[T]) Xor(other *RoaringOf[T]) *RoaringOf[T] {
+	return r.combine(other, xorContainers, true)
+}
+
+// sized returns the values of the set as a SizedOf, filled in a single pass
+// since they're iterated in ascending order
+func (r *RoaringOf[T]) sized() *SizedOf[T] {
+	values := make([]T, 0, r.length)
+	r.Each(func(value T) {
+		values = append(values, value)
+	})
+	return FromSortedOf(values)
+}
+
+// combine merges the keys of r and other, combining the containers of keys
+// they share with f. Containers only in r are copied, as are those only in
+// other when others is true.
+func (r *RoaringOf[T]) combine(other *RoaringOf[T], f func(a, b *container) container, others bool) *RoaringOf[T] {
+	result := NewRoaringOf[T]()
+	i, j := 0, 0
+	for i < len(r.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || (i < len(r.keys) && r.keys[i] < other.keys[j]):
+			result.append(r.keys[i], r.containers[i].clone())
+			i++
+		case i == len(r.keys) || r.keys[i] > other.keys[j]:
+			if others {
+				result.append(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			result.append(r.keys[i], f(&r.containers[i], &other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// append adds a container for a key greater than any in the set, unless it's empty
+func (r *RoaringOf[T]) append(key uint64, c container) {
+	if c.length == 0 {
+		return
+	}
+	r.keys = append(r.keys, key)
+	r.containers = append(r.containers, c)
+	r.length += c.length
+}
+
+// find returns the index of key, or the index it should be inserted at
+func (r *RoaringOf[T]) find(key uint64) (int, bool) {
+	i, j := 0, len(r.keys)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if r.keys[h] < key {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i, i < len(r.keys) && r.keys[i] == key
+}
+
+// split returns the container key and low bits of value. The sign bit of
+// signed values is flipped so that keys sort like the values they hold.
+func (r *RoaringOf[T]) split(value T) (uint64, uint16) {
+	u := uint64(value) ^ signBit[T]()
+	return u >> 16, uint16(u)
+}
+
+// join is the inverse of split
+func (r *RoaringOf[T]) join(key uint64, low uint16) T {
+	return T((key<<16 | uint64(low)) ^ signBit[T]())
+}
+
+// signBit returns the bit flipped to order signed values as unsigned ones
+func signBit[T Integer]() uint64 {
+	if isSigned[T]() {
+		return 1 << 63
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+package intset
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+var (
+	_ Set     = (*Roaring)(nil)
+	_ Set32   = (*Roaring32)(nil)
+	_ SetRune = (*RoaringRune)(nil)
+)
+
+func Test_Roaring_MatchesOracle(t *testing.T) {
+	// sparse values spread over many containers, then dense values which
+	// convert a container between an array and a bitmap
+	AssertOracle[int](t, NewRoaring(), 1, 1<<20)
+	AssertOracle[int](t, NewRoaring(), 2, 10000)
+	AssertOracle[uint32](t, NewRoaring32(), 3, 70000)
+	AssertOracle[rune](t, NewRoaringRune(), 4, 200)
+}
+
+func Test_Roaring_ConvertsContainers(t *testing.T) {
+	r := NewRoaring()
+	for i := 0; i < arrayMax; i++ {
+		r.Set(i * 2)
+	}
+	AssertEqual(t, r.containers[0].kind, uint8(arrayContainer))
+	r.Set(1)
+	AssertEqual(t, r.containers[0].kind, uint8(bitmapContainer))
+	AssertEqual(t, r.Len(), arrayMax+1)
+	AssertTrue(t, r.Remove(1))
+	AssertEqual(t, r.containers[0].kind, uint8(arrayContainer))
+
+	r.Optimize()
+	AssertEqual(t, r.containers[0].kind, uint8(arrayContainer))
+	for i := 0; i < 10000; i++ {
+		r.Set(i)
+	}
+	r.Optimize()
+	AssertEqual(t, r.containers[0].kind, uint8(runContainer))
+	AssertTrue(t, slices.Equal(r.containers[0].runs, []interval{{0, 9999}}))
+	AssertEqual(t, r.Len(), 10000)
+}
+
+func Test_Roaring_RunContainer(t *testing.T) {
+	r := NewRoaring32()
+	for i := uint32(10); i <= 20; i++ {
+		r.Set(i)
+	}
+	r.Optimize()
+	AssertTrue(t, r.Remove(15))
+	AssertTrue(t, r.Remove(10))
+	AssertTrue(t, r.Remove(20))
+	AssertFalse(t, r.Remove(15))
+	AssertTrue(t, slices.Equal(r.containers[0].runs, []interval{{11, 14}, {16, 19}}))
+	r.Set(15)
+	r.Set(10)
+	r.Set(9)
+	r.Set(30)
+	AssertTrue(t, slices.Equal(r.containers[0].runs, []interval{{9, 19}, {30, 30}}))
+	AssertEqual(t, r.Len(), 12)
+	AssertFalse(t, r.Exists(20))
+	AssertTrue(t, r.Exists(30))
+
+	// randomly splitting and joining runs
+	oracle := make(map[uint32]bool)
+	r = NewRoaring32()
+	for i := uint32(0); i < 500; i++ {
+		r.Set(i)
+		oracle[i] = true
+	}
+	r.Optimize()
+	random := rand.New(rand.NewSource(5))
+	for i := 0; i < 5000; i++ {
+		value := uint32(random.Intn(520))
+		if random.Intn(2) == 0 {
+			r.Set(value)
+			oracle[value] = true
+		} else {
+			AssertEqual(t, r.Remove(value), oracle[value])
+			delete(oracle, value)
+		}
+		AssertEqual(t, r.Len(), len(oracle))
+		AssertEqual(t, r.containers[0].kind, uint8(runContainer))
+	}
+	for i := uint32(0); i < 520; i++ {
+		AssertEqual(t, r.Exists(i), oracle[i])
+	}
+
+	// isolated values take more memory as runs than as a bitmap
+	r = NewRoaring32()
+	for i := uint32(0); i < 10; i++ {
+		r.Set(i)
+	}
+	r.Optimize()
+	for i := uint32(5); i <= runMax+5; i++ {
+		r.Set(i * 2)
+	}
+	AssertEqual(t, r.containers[0].kind, uint8(bitmapContainer))
+	AssertEqual(t, r.Len(), runMax+11)
+}
+
+func Test_Roaring_AllIsAscending(t *testing.T) {
+	r := NewRoaringOf[int64]()
+	expected := []int64{-1 << 40, -70000, -3, 0, 5, 65536, 1 << 50}
+	for _, value := range []int64{5, 0, 1 << 50, -3, -70000, 65536, -1 << 40} {
+		r.Set(value)
+	}
+	AssertTrue(t, slices.Equal(slices.Collect(r.All()), expected))
+
+	small := NewRoaringOf[int8]()
+	for i := -128; i < 128; i++ {
+		small.Set(int8(i))
+	}
+	previous := -129
+	small.Each(func(value int8) {
+		AssertEqual(t, int(value), previous+1)
+		previous = int(value)
+	})
+	AssertEqual(t, previous, 127)
+}
+
+func Test_Roaring_Operations(t *testing.T) {
+	r := rand.New(rand.NewSource(21))
+	for round := 0; round < 30; round++ {
+		a, b := randomRoaring(r), randomRoaring(r)
+		and, or, andNot, xor := a.And(b), a.Or(b), a.AndNot(b), a.Xor(b)
+		AssertTrue(t, Equal(and, intersectGeneric[int](Sets{a, b})))
+		AssertTrue(t, Equal(or, unionGeneric[int](Sets{a, b})))
+		AssertTrue(t, Equal(andNot, Difference(a, b)))
+		AssertTrue(t, Equal(xor, SymmetricDifference(a, b)))
+		for _, result := range []*Roaring{and, or, andNot, xor} {
+			count := 0
+			for i, c := range result.containers {
+				AssertTrue(t, c.length > 0)
+				AssertTrue(t, i == 0 || result.keys[i-1] < result.keys[i])
+				count += c.length
+			}
+			AssertEqual(t, count, result.Len())
+		}
+	}
+}
+
+func Test_Roaring_IntersectAndUnionUseContainers(t *testing.T) {
+	r := rand.New(rand.NewSource(22))
+	for round := 0; round < 10; round++ {
+		sets := Sets{randomRoaring(r), randomRoaring(r), randomRoaring(r)}
+		for n := 1; n <= len(sets); n++ {
+			intersection, union := Intersect(sets[:n]...), Union(sets[:n]...)
+			AssertSorted(t, intersection)
+			AssertSorted(t, union)
+			AssertTrue(t, Equal(intersection, intersectGeneric(sets[:n])))
+			AssertTrue(t, Equal(union, unionGeneric(sets[:n])))
+		}
+	}
+	_, ok := allRoaring[int](Sets{NewRoaring(), NewSized(10)})
+	AssertFalse(t, ok)
+}
+
+func Test_Roaring_OperationsDoNotShareContainers(t *testing.T) {
+	a, b := NewRoaring(), NewRoaring()
+	a.Set(1)
+	b.Set(1 << 20)
+	or := a.Or(b)
+	or.Set(2)
+	or.Remove(1 << 20)
+	AssertFalse(t, a.Exists(2))
+	AssertTrue(t, b.Exists(1<<20))
+}
+
+// randomRoaring returns a set mixing sparse values, dense values and ranges,
+// optimized or not, so that every pair of container kinds gets combined
+func randomRoaring(r *rand.Rand) *Roaring {
+	s := NewRoaring()
+	for key := 0; key < 4; key++ {
+		base := key << 16
+		switch r.Intn(4) {
+		case 0:
+			for i := 0; i < 100; i++ {
+				s.Set(base + r.Intn(1<<16))
+			}
+		case 1:
+			for i := 0; i < 6000; i++ {
+				s.Set(base + r.Intn(1<<16))
+			}
+		case 2:
+			for i := 0; i < 5; i++ {
+				start, length := r.Intn(1<<16), r.Intn(3000)
+				for v := start; v < start+length && v < 1<<16; v++ {
+					s.Set(base + v)
+				}
+			}
+		}
+	}
+	if r.Intn(2) == 0 {
+		s.Optimize()
+	}
+	return s
+}
+
+func Benchmark_RoaringDenseExists(b *testing.B) {
+	s := NewRoaring()
+	for i := 0; i < 1000000; i++ {
+		s.Set(i)
+	}
+	s.Optimize()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Exists(i % 1000000)
+	}
+}
@@ -1,184 +1,133 @@
 // Package intset provides a specialized set for integers or runes
 package intset
 
-import "sort"
+import "iter"
 
 // SetRune defines rune set methods
-type SetRune interface {
-	Len() int
-	Exists(value rune) bool
-	Each(f func(value rune))
-}
+type SetRune = SetOf[rune]
 
 // SetsRune is array of Rune
-type SetsRune []SetRune
+type SetsRune = SetsOf[rune]
+
+// Rune stores rune set data
+type Rune = SizedOf[rune]
 
-func (s SetsRune) Len() int {
-	return len(s)
+// NewRune creates an empty rune set with target capacity specified by size using default configuration
+func NewRune(size rune) *Rune {
+	return NewSizedOf[rune](int(size))
 }
 
-func (s SetsRune) Less(i, j int) bool {
-	return s[i].Len() < s[j].Len()
+// NewRuneConfig creates an empty rune set with target capacity specified by size
+func NewRuneConfig(size rune, config *Config) *Rune {
+	return NewSizedOfConfig[rune](int(size), config)
 }
 
-func (s SetsRune) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
+// IntersectRune returns the intersection of sets, or an empty set when there are none
+func IntersectRune(sets ...SetRune) *Rune {
+	return IntersectOf(sets...)
 }
 
-// Rune stores rune set data
-type Rune struct {
-	mask    rune
-	buckets [][]rune
-	length  int
-	growBy  int
+// IntersectIntoRune appends the intersection of sets to dst,
+// stopping once limit values have been appended (0 for no limit)
+func IntersectIntoRune(dst []rune, limit int, sets ...SetRune) []rune {
+	return IntersectIntoOf(dst, limit, sets...)
 }
 
-// NewRune creates an empty rune set with target capacity specified by size using default configuration
-func NewRune(size rune) *Rune {
-	return NewRuneConfig(size, Default)
+// UnionRune returns the union of sets, or an empty set when there are none
+func UnionRune(sets ...SetRune) *Rune {
+	return UnionOf(sets...)
 }
 
-// NewRuneConfig creates an empty rune set with target capacity specified by size
-func NewRuneConfig(size rune, config *Config) *Rune {
-	if size < rune(config.bucketSize) {
-		size = rune(config.bucketSize)
-	}
-	count := upTwo(int(size) / config.bucketSize)
-	s := &Rune{
-		mask:    rune(count) - 1,
-		buckets: make([][]rune, count),
-		growBy:  config.bucketGrowBy,
-	}
-	return s
-}
-
-// Set adds a value to the rune set
-func (s *Rune) Set(value rune) {
-	index := value & s.mask
-	bucket := s.buckets[index]
-	position, exists := s.index(value, bucket)
-	if exists {
-		return
-	}
-	l := len(bucket)
-	if cap(bucket) == l {
-		n := make([]rune, l, l+s.growBy)
-		copy(n, bucket)
-		bucket = n
-	}
-	bucket = append(bucket, value)
-	if position != l {
-		copy(bucket[position+1:], bucket[position:])
-		bucket[position] = value
-	}
-	s.length++
-	s.buckets[index] = bucket
-}
-
-// Remove returns true if the value existed in the rune set before being removed
-func (s *Rune) Remove(value rune) bool {
-	index := value & s.mask
-	bucket := s.buckets[index]
-	position, exists := s.index(value, bucket)
-	if exists == false {
-		return false
-	}
-	l := len(bucket) - 1
-	bucket[position], bucket[l] = bucket[l], bucket[position]
-	s.buckets[index] = bucket[:l]
-	s.length--
-	return true
-}
-
-// Exists returns true if the value exists in the set
-func (s *Rune) Exists(value rune) bool {
-	return s.exists(value, s.buckets[value&s.mask])
-}
-
-// Len returns the total number of elements in the set
-func (s Rune) Len() int {
-	return s.length
-}
-
-// Each iterates through the set items and applies function f to each set item
-func (s Rune) Each(f func(value rune)) {
-	for _, bucket := range s.buckets {
-		for _, value := range bucket {
-			f(value)
-		}
-	}
-}
-
-func (s Rune) index(value rune, bucket []rune) (int, bool) {
-	l := len(bucket)
-	if l == 0 {
-		return 0, false
-	}
-
-	l = l / 2
-	v := bucket[l]
-	if value == v {
-		return l, true
-	}
-	if value > v {
-		bucket = bucket[l:]
-	} else {
-		l = 0
-	}
-
-	var i int
-	for i, v = range bucket {
-		if v >= value {
-			return l + i, v == value
-		}
-	}
-	return l + i + 1, false
-}
-
-func (s Rune) exists(value rune, bucket []rune) bool {
-	if len(bucket) == 0 {
-		return false
-	}
-
-	for _, v := range bucket {
-		if v >= value {
-			return v == value
-		}
-	}
-	return false
-}
-
-// IntersectRune returns the intersection of an array of sets
-func IntersectRune(sets SetsRune) *Rune {
-	sort.Sort(sets)
-	a, l := sets[0], sets.Len()
-	values := make([]rune, 0, a.Len())
-	a.Each(func(value rune) {
-		for i := 1; i < l; i++ {
-			if sets[i].Exists(value) == false {
-				return
-			}
-		}
-		values = append(values, value)
-	})
-	s := NewRune(rune(len(values)))
-	for _, value := range values {
-		s.Set(value)
-	}
-	return s
-}
-
-// UnionRune returns the union of an array of sets
-func UnionRune(sets SetsRune) *Rune {
-	values := make(map[rune]struct{}, sets[0].Len())
-	for i := 0; i < sets.Len(); i++ {
-		sets[i].Each(func(value rune) {
-			values[value] = struct{}{}
-		})
-	}
-	s := NewRune(rune(len(values)))
-	for value := range values {
-		s.Set(value)
-	}
-	return s
+// DifferenceRune returns the values of a which aren't in b
+func DifferenceRune(a, b SetRune) *Rune {
+	return DifferenceOf(a, b)
+}
+
+// SymmetricDifferenceRune returns the values which are in either a or b, but not both
+func SymmetricDifferenceRune(a, b SetRune) *Rune {
+	return SymmetricDifferenceOf(a, b)
+}
+
+// IsSubsetRune returns true if every value of a is in b
+func IsSubsetRune(a, b SetRune) bool {
+	return IsSubsetOf(a, b)
+}
+
+// IsSupersetRune returns true if every value of b is in a
+func IsSupersetRune(a, b SetRune) bool {
+	return IsSupersetOf(a, b)
+}
+
+// DisjointRune returns true if a and b have no value in common
+func DisjointRune(a, b SetRune) bool {
+	return DisjointOf(a, b)
+}
+
+// EqualRune returns true if a and b contain the same values
+func EqualRune(a, b SetRune) bool {
+	return EqualOf(a, b)
+}
+
+// IntersectCountRune returns the number of values in every set
+func IntersectCountRune(sets ...SetRune) int {
+	return IntersectCountOf(sets...)
+}
+
+// UnionCountRune returns the number of values in at least one set
+func UnionCountRune(sets ...SetRune) int {
+	return UnionCountOf(sets...)
+}
+
+// DifferenceCountRune returns the number of values of a which aren't in b
+func DifferenceCountRune(a, b SetRune) int {
+	return DifferenceCountOf(a, b)
+}
+
+// JaccardRune returns the Jaccard index of a and b, |a ∩ b| / |a ∪ b|
+func JaccardRune(a, b SetRune) float64 {
+	return JaccardOf(a, b)
+}
+
+// OverlapRune returns the overlap coefficient of a and b, |a ∩ b| / min(|a|, |b|)
+func OverlapRune(a, b SetRune) float64 {
+	return OverlapOf(a, b)
+}
+
+// ParallelIntersectRune returns the intersection of sets, splitting the work
+// across workers goroutines (0 for GOMAXPROCS)
+func ParallelIntersectRune(workers int, sets ...SetRune) *Rune {
+	return ParallelIntersectOf(workers, sets...)
+}
+
+// ParallelUnionRune returns the union of sets, splitting the work across
+// workers goroutines (0 for GOMAXPROCS)
+func ParallelUnionRune(workers int, sets ...SetRune) *Rune {
+	return ParallelUnionOf(workers, sets...)
+}
+
+// AtLeastRune returns the values which are in at least k of the sets
+func AtLeastRune(k int, sets ...SetRune) *Rune {
+	return AtLeastOf(k, sets...)
+}
+
+// CountsRune calls f once for every value in at least one set, with the number
+// of sets which have it
+func CountsRune(f func(value rune, count int), sets ...SetRune) {
+	CountsOf(f, sets...)
+}
+
+// FromSliceRune creates a rune set holding values, using default configuration
+func FromSliceRune(values []rune) *Rune {
+	return FromSliceOf(values)
+}
+
+// FromSortedRune creates a rune set holding values, which should be sorted in
+// ascending order, using default configuration
+func FromSortedRune(values []rune) *Rune {
+	return FromSortedOf(values)
+}
+
+// CollectRune creates a rune set holding the values of seq, using default configuration
+func CollectRune(seq iter.Seq[rune]) *Rune {
+	return CollectOf(seq)
 }
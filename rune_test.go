@@ -62,7 +62,7 @@ func Test_Rune_IntersectsTwoSets(t *testing.T) {
 	s2.Set(3)
 	s2.Set(4)
 
-	s := IntersectRune([]SetRune{s1, s2})
+	s := IntersectRune(s1, s2)
 	AssertFalse(t, s.Exists(1))
 	AssertTrue(t, s.Exists(2))
 	AssertTrue(t, s.Exists(3))
@@ -81,7 +81,7 @@ func Test_Rune_UnionsTwoSets(t *testing.T) {
 	s2.Set(3)
 	s2.Set(4)
 
-	s := UnionRune([]SetRune{s1, s2})
+	s := UnionRune(s1, s2)
 	AssertTrue(t, s.Exists(1))
 	AssertTrue(t, s.Exists(2))
 	AssertTrue(t, s.Exists(3))
@@ -89,6 +89,53 @@ func Test_Rune_UnionsTwoSets(t *testing.T) {
 	AssertFalse(t, s.Exists(5))
 }
 
+func Test_Rune_RemoveKeepsBucketsSorted(t *testing.T) {
+	s := NewRune(4)
+	for i := 0; i < 8; i++ {
+		s.Set(rune(i * 4))
+	}
+	AssertTrue(t, s.Remove(0))
+	for i := 1; i < 8; i++ {
+		AssertTrue(t, s.Exists(rune(i*4)))
+	}
+}
+
+func Test_Rune_MatchesOracle(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		AssertOracle(t, NewRune(64), seed, 512)
+		AssertOracle(t, NewRune(1000), seed, 1<<16)
+	}
+}
+
+func Test_Rune_Resize(t *testing.T) {
+	s := NewRuneConfig(16, NewConfig().AutoGrow(2))
+	for i := rune(0); i < 100; i++ {
+		s.Set(i * 3)
+	}
+	AssertEqual(t, len(s.buckets), 64)
+	s.Resize(4)
+	AssertEqual(t, len(s.buckets), 1)
+	for i := rune(0); i < 100; i++ {
+		AssertTrue(t, s.Exists(i*3))
+		AssertFalse(t, s.Exists(i*3+1))
+	}
+}
+
+func Test_Rune_IntersectInto(t *testing.T) {
+	s1 := NewRune(10)
+	s2 := NewRune(10)
+	for _, r := range "abcdef" {
+		s1.Set(r)
+	}
+	for _, r := range "bdf" {
+		s2.Set(r)
+	}
+	values := IntersectIntoRune(nil, 2, s1, s2)
+	AssertEqual(t, len(values), 2)
+	AssertTrue(t, s2.Exists(values[0]))
+	AssertTrue(t, s2.Exists(values[1]))
+}
+
 func Test_SwapRune(t *testing.T) {
 	s1 := NewRune(1)
 	s1.Set(0)
@@ -149,7 +196,7 @@ func Benchmark_RuneDenseIntersect(b *testing.B) {
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		IntersectRune([]SetRune{s1, s2})
+		IntersectRune(s1, s2)
 	}
 }
 
@@ -1,68 +1,140 @@
 // Package intset provides a specialized set for integers or runes
 package intset
 
-import "sort"
+import (
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+	"sort"
+)
 
-// Set defines int set methods
-type Set interface {
+// SetOf defines set methods for any integer type
+type SetOf[T Integer] interface {
 	Len() int
-	Exists(value int) bool
-	Each(f func(value int))
+	Exists(value T) bool
+	Each(f func(value T))
+	All() iter.Seq[T]
 }
 
-// Sets is array of Set
-type Sets []Set
+// SetsOf is array of SetOf
+type SetsOf[T Integer] []SetOf[T]
 
-func (s Sets) Len() int {
+func (s SetsOf[T]) Len() int {
 	return len(s)
 }
 
-func (s Sets) Less(i, j int) bool {
+func (s SetsOf[T]) Less(i, j int) bool {
 	return s[i].Len() < s[j].Len()
 }
 
-func (s Sets) Swap(i, j int) {
+func (s SetsOf[T]) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
 }
 
+// Set defines int set methods
+type Set = SetOf[int]
+
+// Sets is array of Set
+type Sets = SetsOf[int]
+
+// SizedOf stores set data for any integer type
+type SizedOf[T Integer] struct {
+	mask       uint64
+	buckets    [][]T
+	length     int
+	growBy     int
+	bucketSize int
+	growLoad   float64
+	shrinkLoad float64
+	growAt     int
+	shrinkAt   int
+	hash       func(uint64) uint64
+	// buckets still backed by another set's arrays, copied before being modified
+	shared []bool
+}
+
 // Sized stores int set data
-type Sized struct {
-	mask    int
-	buckets [][]int
-	length  int
-	growBy  int
+type Sized = SizedOf[int]
+
+// NewSizedOf creates an empty set with target capacity specified by size using default configuration
+func NewSizedOf[T Integer](size int) *SizedOf[T] {
+	return NewSizedOfConfig[T](size, Default)
+}
+
+// NewSizedOfConfig creates an empty set with target capacity specified by size
+func NewSizedOfConfig[T Integer](size int, config *Config) *SizedOf[T] {
+	count := bucketCount[T](size, config.bucketSize)
+	s := &SizedOf[T]{
+		mask:       uint64(count) - 1,
+		buckets:    make([][]T, count),
+		growBy:     config.bucketGrowBy,
+		bucketSize: config.bucketSize,
+		growLoad:   config.growLoad,
+		shrinkLoad: config.shrinkLoad,
+		hash:       config.hash,
+	}
+	s.thresholds()
+	return s
+}
+
+// bucketCount returns the number of buckets needed to hold size values
+func bucketCount[T Integer](size int, bucketSize int) int {
+	if size < bucketSize {
+		size = bucketSize
+	}
+	count := upTwo(size / bucketSize)
+	// no point having more buckets than T has distinct values
+	if bits := bitsOf[T](); bits < 32 && count > 1<<bits {
+		count = 1 << bits
+	}
+	return count
 }
 
 // NewSized creates an empty int set with target capacity specified by size using default configuration
 func NewSized(size int) *Sized {
-	return NewSizedConfig(size, Default)
+	return NewSizedOf[int](size)
 }
 
 // NewSizedConfig creates an empty int set with target capacity specified by size
 func NewSizedConfig(size int, config *Config) *Sized {
-	if size < config.bucketSize {
-		size = config.bucketSize
+	return NewSizedOfConfig[int](size, config)
+}
+
+// Set adds a value to the set
+func (s *SizedOf[T]) Set(value T) {
+	if s.insert(s.bucket(value), value) == false {
+		return
 	}
-	count := upTwo(size / config.bucketSize)
-	s := &Sized{
-		mask:    count - 1,
-		buckets: make([][]int, count),
-		growBy:  config.bucketGrowBy,
+	s.length++
+	if s.growAt != 0 && s.length >= s.growAt {
+		s.rehash(len(s.buckets) * 2)
 	}
-	return s
 }
 
-// Set adds a value to the int set
-func (s *Sized) Set(value int) {
-	index := value & s.mask
+// Remove returns true if the value existed in the set before being removed
+func (s *SizedOf[T]) Remove(value T) bool {
+	if s.delete(s.bucket(value), value) == false {
+		return false
+	}
+	s.length--
+	if s.length < s.shrinkAt {
+		s.rehash(len(s.buckets) / 2)
+	}
+	return true
+}
+
+// insert adds value to the bucket at index, returning false if it already existed
+func (s *SizedOf[T]) insert(index uint64, value T) bool {
 	bucket := s.buckets[index]
 	position, exists := s.index(value, bucket)
 	if exists {
-		return
+		return false
 	}
+	bucket = s.own(index)
 	l := len(bucket)
 	if cap(bucket) == l {
-		n := make([]int, l, l+s.growBy)
+		n := make([]T, l, l+s.growBy)
 		copy(n, bucket)
 		bucket = n
 	}
@@ -71,37 +143,93 @@ func (s *Sized) Set(value int) {
 		copy(bucket[position+1:], bucket[position:])
 		bucket[position] = value
 	}
-	s.length++
 	s.buckets[index] = bucket
+	return true
 }
 
-// Remove returns true if the value existed in the int set before being removed
-func (s *Sized) Remove(value int) bool {
-	index := value & s.mask
+// delete removes value from the bucket at index, returning false if it didn't exist
+func (s *SizedOf[T]) delete(index uint64, value T) bool {
 	bucket := s.buckets[index]
 	position, exists := s.index(value, bucket)
 	if exists == false {
 		return false
 	}
-	l := len(bucket) - 1
-	bucket[position], bucket[l] = bucket[l], bucket[position]
-	s.buckets[index] = bucket[:l]
-	s.length--
+	bucket = s.own(index)
+	// shift rather than swap, index and exists rely on buckets being sorted
+	copy(bucket[position:], bucket[position+1:])
+	s.buckets[index] = bucket[:len(bucket)-1]
 	return true
 }
 
+// own makes sure the bucket at index isn't shared with another set, returning it
+func (s *SizedOf[T]) own(index uint64) []T {
+	bucket := s.buckets[index]
+	if s.shared == nil || s.shared[index] == false {
+		return bucket
+	}
+	n := make([]T, len(bucket), len(bucket)+s.growBy)
+	copy(n, bucket)
+	s.buckets[index] = n
+	s.shared[index] = false
+	return n
+}
+
+// like returns an empty set with the same configuration and number of buckets
+func (s *SizedOf[T]) like() *SizedOf[T] {
+	l := &SizedOf[T]{
+		mask:       s.mask,
+		buckets:    make([][]T, len(s.buckets)),
+		growBy:     s.growBy,
+		bucketSize: s.bucketSize,
+		growLoad:   s.growLoad,
+		shrinkLoad: s.shrinkLoad,
+		hash:       s.hash,
+	}
+	l.thresholds()
+	return l
+}
+
+// fill returns a set configured like s, whose bucket i is
+// values[offsets[i]:offsets[i+1]]
+func (s *SizedOf[T]) fill(values []T, offsets []int) *SizedOf[T] {
+	f := s.like()
+	for i := range f.buckets {
+		f.buckets[i] = values[offsets[i]:offsets[i+1]:offsets[i+1]]
+	}
+	f.length = len(values)
+	return f
+}
+
+// clone returns a copy of the set which shares its buckets until they're modified
+func (s *SizedOf[T]) clone() *SizedOf[T] {
+	c := *s
+	c.buckets = make([][]T, len(s.buckets))
+	copy(c.buckets, s.buckets)
+	c.shared = make([]bool, len(s.buckets))
+	for i := range c.shared {
+		c.shared[i] = true
+	}
+	return &c
+}
+
+// Resize redistributes the values over the number of buckets a set created
+// with the target capacity specified by size would have
+func (s *SizedOf[T]) Resize(size int) {
+	s.rehash(bucketCount[T](size, s.bucketSize))
+}
+
 // Exists returns true if the value exists in the set
-func (s *Sized) Exists(value int) bool {
-	return s.exists(value, s.buckets[value&s.mask])
+func (s *SizedOf[T]) Exists(value T) bool {
+	return s.exists(value, s.buckets[s.bucket(value)])
 }
 
 // Len returns the total number of elements in the set
-func (s Sized) Len() int {
+func (s SizedOf[T]) Len() int {
 	return s.length
 }
 
 // Each iterates through the set items and applies function f to each set item
-func (s Sized) Each(f func(value int)) {
+func (s SizedOf[T]) Each(f func(value T)) {
 	for _, bucket := range s.buckets {
 		for _, value := range bucket {
 			f(value)
@@ -109,7 +237,93 @@ func (s Sized) Each(f func(value int)) {
 	}
 }
 
-func (s Sized) index(value int, bucket []int) (int, bool) {
+// reset empties the set, sizing it for size values. A zero value set uses the
+// default configuration.
+func (s *SizedOf[T]) reset(size int) {
+	if s.bucketSize == 0 {
+		*s = *NewSizedOfConfig[T](size, Default)
+		return
+	}
+	count := bucketCount[T](size, s.bucketSize)
+	s.mask = uint64(count) - 1
+	s.buckets = make([][]T, count)
+	s.shared = nil
+	s.length = 0
+	s.thresholds()
+}
+
+// fit rehashes, after a bulk change, to the number of buckets AutoGrow and
+// AutoShrink would have settled on
+func (s *SizedOf[T]) fit() {
+	count := len(s.buckets)
+	if s.growLoad > 0 {
+		for float64(s.length) > s.growLoad*float64(count) {
+			count *= 2
+		}
+	}
+	if s.shrinkLoad > 0 {
+		for count > 1 && float64(s.length) < s.shrinkLoad*float64(count) {
+			count /= 2
+		}
+	}
+	s.rehash(count)
+}
+
+// bucket returns the index of the bucket value belongs in
+func (s *SizedOf[T]) bucket(value T) uint64 {
+	return s.hashed(value) & s.mask
+}
+
+// hashed returns the configured hash of value
+func (s *SizedOf[T]) hashed(value T) uint64 {
+	if s.hash == nil {
+		return uint64(value)
+	}
+	return s.hash(uint64(value))
+}
+
+// rehash redistributes the values over count buckets
+func (s *SizedOf[T]) rehash(count int) {
+	if bits := bitsOf[T](); bits < 32 && count > 1<<bits {
+		count = 1 << bits
+	}
+	if count == len(s.buckets) {
+		return
+	}
+	mask := uint64(count) - 1
+	buckets := make([][]T, count)
+	for _, bucket := range s.buckets {
+		for _, value := range bucket {
+			index := s.hashed(value) & mask
+			buckets[index] = append(buckets[index], value)
+		}
+	}
+	// when growing, each new bucket is filled from a single sorted old bucket,
+	// when shrinking, several old buckets are merged together
+	if count < len(s.buckets) {
+		for _, bucket := range buckets {
+			sort.Slice(bucket, func(i, j int) bool { return bucket[i] < bucket[j] })
+		}
+	}
+	s.mask = mask
+	s.buckets = buckets
+	s.shared = nil
+	s.thresholds()
+}
+
+// thresholds computes the lengths at which the set grows or shrinks
+func (s *SizedOf[T]) thresholds() {
+	count := float64(len(s.buckets))
+	s.growAt, s.shrinkAt = 0, 0
+	if s.growLoad > 0 {
+		s.growAt = int(s.growLoad*count) + 1
+	}
+	if s.shrinkLoad > 0 && len(s.buckets) > 1 {
+		s.shrinkAt = int(math.Ceil(s.shrinkLoad * count))
+	}
+}
+
+func (s SizedOf[T]) index(value T, bucket []T) (int, bool) {
 	l := len(bucket)
 	if l == 0 {
 		return 0, false
@@ -135,7 +349,7 @@ func (s Sized) index(value int, bucket []int) (int, bool) {
 	return l + i + 1, false
 }
 
-func (s Sized) exists(value int, bucket []int) bool {
+func (s SizedOf[T]) exists(value T, bucket []T) bool {
 	if len(bucket) == 0 {
 		return false
 	}
@@ -148,37 +362,282 @@ func (s Sized) exists(value int, bucket []int) bool {
 	return false
 }
 
-// Intersect returns the intersection of an array of sets
-func Intersect(sets Sets) *Sized {
-	sort.Sort(sets)
-	a, l := sets[0], sets.Len()
-	values := make([]int, 0, a.Len())
-	a.Each(func(value int) {
-		for i := 1; i < l; i++ {
-			if sets[i].Exists(value) == false {
-				return
+// IntersectOf returns the intersection of sets, or an empty set when there are none
+func IntersectOf[T Integer](sets ...SetOf[T]) *SizedOf[T] {
+	if len(sets) == 0 {
+		return NewSizedOf[T](0)
+	}
+	if sized, ok := allSized(sets); ok {
+		return intersectSized(sized)
+	}
+	if roaring, ok := allRoaring(sets); ok {
+		result := roaring[0]
+		for _, r := range roaring[1:] {
+			result = result.And(r)
+		}
+		return result.sized()
+	}
+	return intersectGeneric(sets)
+}
+
+// UnionOf returns the union of sets, or an empty set when there are none
+func UnionOf[T Integer](sets ...SetOf[T]) *SizedOf[T] {
+	if len(sets) == 0 {
+		return NewSizedOf[T](0)
+	}
+	if sized, ok := allSized(sets); ok {
+		return unionSized(sized)
+	}
+	if roaring, ok := allRoaring(sets); ok {
+		result := roaring[0]
+		for _, r := range roaring[1:] {
+			result = result.Or(r)
+		}
+		return result.sized()
+	}
+	return unionGeneric(sets)
+}
+
+// IntersectIntoOf appends the intersection of sets to dst,
+// stopping once limit values have been appended (0 for no limit). It doesn't
+// allocate when dst has enough capacity and the smallest set is a SizedOf.
+func IntersectIntoOf[T Integer](dst []T, limit int, sets ...SetOf[T]) []T {
+	if len(sets) == 0 {
+		return dst
+	}
+	driver := 0
+	for i, set := range sets {
+		if set.Len() < sets[driver].Len() {
+			driver = i
+		}
+	}
+	s, ok := sets[driver].(*SizedOf[T])
+	if ok == false {
+		return intersectIntoSeq(dst, limit, sets, driver)
+	}
+	added := 0
+	for _, bucket := range s.buckets {
+		for _, value := range bucket {
+			if inAll(sets, driver, value) {
+				dst = append(dst, value)
+				if added++; added == limit {
+					return dst
+				}
 			}
 		}
-		values = append(values, value)
-	})
-	s := NewSized(len(values))
-	for _, value := range values {
-		s.Set(value)
 	}
-	return s
+	return dst
+}
+
+func intersectIntoSeq[T Integer](dst []T, limit int, sets SetsOf[T], driver int) []T {
+	added := 0
+	for value := range sets[driver].All() {
+		if inAll(sets, driver, value) {
+			dst = append(dst, value)
+			if added++; added == limit {
+				break
+			}
+		}
+	}
+	return dst
 }
 
-// Union returns the union of an array of sets
-func Union(sets Sets) *Sized {
-	values := make(map[int]struct{}, sets[0].Len())
+func intersectGeneric[T Integer](sets SetsOf[T]) *SizedOf[T] {
+	driver := 0
+	for i, set := range sets {
+		if set.Len() < sets[driver].Len() {
+			driver = i
+		}
+	}
+	values := make([]T, 0, sets[driver].Len())
+	sets[driver].Each(func(value T) {
+		if inAll(sets, driver, value) {
+			values = append(values, value)
+		}
+	})
+	return FromSliceOf(values)
+}
+
+func unionGeneric[T Integer](sets SetsOf[T]) *SizedOf[T] {
+	values := make(map[T]struct{}, sets[0].Len())
 	for i := 0; i < sets.Len(); i++ {
-		sets[i].Each(func(value int) {
+		sets[i].Each(func(value T) {
 			values[value] = struct{}{}
 		})
 	}
-	s := NewSized(len(values))
+	s := NewSizedOf[T](len(values))
 	for value := range values {
 		s.Set(value)
 	}
 	return s
 }
+
+// allSized returns sets as SizedOf if they all select buckets from the value's
+// low bits. Since bucket counts are powers of two, value v is then in bucket
+// v & mask of every set, and sets can be combined bucket by bucket.
+func allSized[T Integer](sets SetsOf[T]) ([]*SizedOf[T], bool) {
+	sized := make([]*SizedOf[T], len(sets))
+	for i, set := range sets {
+		s, ok := set.(*SizedOf[T])
+		if ok == false || s.hash != nil {
+			return nil, false
+		}
+		sized[i] = s
+	}
+	return sized, true
+}
+
+// allRoaring returns sets as RoaringOf if they all are, in which case they're
+// combined container by container with And and Or
+func allRoaring[T Integer](sets SetsOf[T]) ([]*RoaringOf[T], bool) {
+	roaring := make([]*RoaringOf[T], len(sets))
+	for i, set := range sets {
+		r, ok := set.(*RoaringOf[T])
+		if ok == false {
+			return nil, false
+		}
+		roaring[i] = r
+	}
+	return roaring, true
+}
+
+// intersectSized intersects sets bucket by bucket. The result has as many
+// buckets as the set with the fewest, whose bucket i holds every value which
+// can be in bucket i of the result.
+func intersectSized[T Integer](sets []*SizedOf[T]) *SizedOf[T] {
+	driver := intersectDriver(sets)
+	offsets := make([]int, len(driver.buckets)+1)
+	values := make([]T, 0, driver.length)
+	values = intersectBuckets(sets, driver, 0, len(driver.buckets), values, offsets)
+	return driver.fill(values, offsets)
+}
+
+// intersectDriver returns the set with the fewest buckets, and then values,
+// whose buckets drive intersectBuckets
+func intersectDriver[T Integer](sets []*SizedOf[T]) *SizedOf[T] {
+	driver := sets[0]
+	for _, set := range sets[1:] {
+		if set.mask < driver.mask || (set.mask == driver.mask && set.length < driver.length) {
+			driver = set
+		}
+	}
+	return driver
+}
+
+// intersectBuckets appends the intersection of the driver's buckets [lo, hi)
+// to values, setting offsets[i+1] to the length of values once bucket i is done
+func intersectBuckets[T Integer](sets []*SizedOf[T], driver *SizedOf[T], lo, hi int, values []T, offsets []int) []T {
+	var cursors []int
+	shift := bits.OnesCount64(driver.mask)
+	for i := lo; i < hi; i++ {
+		start := len(values)
+		values = append(values, driver.buckets[i]...)
+		for _, set := range sets {
+			if set == driver || len(values) == start {
+				continue
+			}
+			if set.mask == driver.mask {
+				values = values[:start+len(keepIntersection(values[start:], set.buckets[i]))]
+			} else {
+				width := int(set.mask>>shift) + 1
+				cursors = slices.Grow(cursors[:0], width)[:width]
+				clear(cursors)
+				values = values[:start+len(set.keepMerged(values[start:], shift, cursors))]
+			}
+		}
+		offsets[i+1] = len(values)
+	}
+	return values
+}
+
+// keepMerged filters sorted values, in place, to those in the set, when they
+// all come from the same bucket of a set with 1<<shift buckets. The set has
+// more buckets, and that bucket's values are spread over its buckets i,
+// i+1<<shift, i+2<<shift... Each of those is merged with values through its
+// own cursor, cursors[j] tracking bucket i+j<<shift, rather than searched.
+func (s *SizedOf[T]) keepMerged(values []T, shift int, cursors []int) []T {
+	n := 0
+	for _, value := range values {
+		index := s.bucket(value)
+		bucket := s.buckets[index]
+		p := cursors[index>>shift]
+		for p < len(bucket) && bucket[p] < value {
+			p++
+		}
+		cursors[index>>shift] = p
+		if p < len(bucket) && bucket[p] == value {
+			values[n] = value
+			n++
+		}
+	}
+	return values[:n]
+}
+
+// unionSized unions sets bucket by bucket. The result has as many buckets as
+// the set with the most, whose bucket j is the union of bucket j & mask of
+// every set, limited to the values which belong in bucket j.
+func unionSized[T Integer](sets []*SizedOf[T]) *SizedOf[T] {
+	widest := unionWidest(sets)
+	total := 0
+	for _, set := range sets {
+		total += set.length
+	}
+	offsets := make([]int, len(widest.buckets)+1)
+	values := make([]T, 0, total)
+	values = unionBuckets(sets, widest.mask, 0, len(widest.buckets), values, offsets)
+	return widest.fill(values, offsets)
+}
+
+// unionWidest returns the set with the most buckets, whose layout the union has
+func unionWidest[T Integer](sets []*SizedOf[T]) *SizedOf[T] {
+	widest := sets[0]
+	for _, set := range sets[1:] {
+		if set.mask > widest.mask {
+			widest = set
+		}
+	}
+	return widest
+}
+
+// unionBuckets appends the union of buckets [lo, hi) of a set with the given
+// mask to values, setting offsets[j+1] to the length of values once bucket j
+// is done
+func unionBuckets[T Integer](sets []*SizedOf[T], mask uint64, lo, hi int, values []T, offsets []int) []T {
+	var merged, scratch, filtered []T
+	for j := lo; j < hi; j++ {
+		merged = merged[:0]
+		for _, set := range sets {
+			bucket := set.buckets[uint64(j)&set.mask]
+			if set.mask != mask {
+				filtered = filtered[:0]
+				for _, value := range bucket {
+					if uint64(value)&mask == uint64(j) {
+						filtered = append(filtered, value)
+					}
+				}
+				bucket = filtered
+			}
+			scratch = appendUnion(scratch[:0], merged, bucket)
+			merged, scratch = scratch, merged
+		}
+		values = append(values, merged...)
+		offsets[j+1] = len(values)
+	}
+	return values
+}
+
+// Intersect returns the intersection of sets, or an empty set when there are none
+func Intersect(sets ...Set) *Sized {
+	return IntersectOf(sets...)
+}
+
+// IntersectInto appends the intersection of sets to dst, stopping
+// once limit values have been appended (0 for no limit)
+func IntersectInto(dst []int, limit int, sets ...Set) []int {
+	return IntersectIntoOf(dst, limit, sets...)
+}
+
+// Union returns the union of sets, or an empty set when there are none
+func Union(sets ...Set) *Sized {
+	return UnionOf(sets...)
+}
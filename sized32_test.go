@@ -63,7 +63,7 @@ func Test_Sized32_IntersectsTwoSets(t *testing.T) {
 	s2.Set(3)
 	s2.Set(4)
 
-	s := Intersect32([]Set32{s1, s2})
+	s := Intersect32(s1, s2)
 	AssertFalse(t, s.Exists(1))
 	AssertTrue(t, s.Exists(2))
 	AssertTrue(t, s.Exists(3))
@@ -82,7 +82,7 @@ func Test_Sized32_UnionsTwoSets(t *testing.T) {
 	s2.Set(3)
 	s2.Set(4)
 
-	s := Union32([]Set32{s1, s2})
+	s := Union32(s1, s2)
 	AssertTrue(t, s.Exists(1))
 	AssertTrue(t, s.Exists(2))
 	AssertTrue(t, s.Exists(3))
@@ -90,6 +90,49 @@ func Test_Sized32_UnionsTwoSets(t *testing.T) {
 	AssertFalse(t, s.Exists(5))
 }
 
+func Test_Sized32_RemoveKeepsBucketsSorted(t *testing.T) {
+	s := NewSized32(4)
+	for i := 0; i < 8; i++ {
+		s.Set(uint32(i * 4))
+	}
+	AssertTrue(t, s.Remove(0))
+	for i := 1; i < 8; i++ {
+		AssertTrue(t, s.Exists(uint32(i*4)))
+	}
+}
+
+func Test_Sized32_MatchesOracle(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		AssertOracle(t, NewSized32(64), seed, 512)
+		AssertOracle(t, NewSized32(1000), seed, 1<<16)
+	}
+}
+
+func Test_Sized32_Resize(t *testing.T) {
+	s := NewSized32(16)
+	for i := uint32(0); i < 100; i++ {
+		s.Set(i * 3)
+	}
+	s.Resize(400)
+	AssertEqual(t, len(s.buckets), 128)
+	s.Resize(4)
+	AssertEqual(t, len(s.buckets), 1)
+	for i := uint32(0); i < 100; i++ {
+		AssertTrue(t, s.Exists(i*3))
+		AssertFalse(t, s.Exists(i*3+1))
+	}
+}
+
+func Test_Sized32_IntersectInto(t *testing.T) {
+	s1 := NewSized32(10)
+	s2 := NewSized32(10)
+	s1.Set(1)
+	s1.Set(2)
+	s2.Set(2)
+	values := IntersectInto32(make([]uint32, 0, 4), 10, s1, s2)
+	assertSequence(t, values, []uint32{2})
+}
+
 func Test_Swap32(t *testing.T) {
 	s1 := NewSized32(1)
 	s1.Set(0)
@@ -150,7 +193,7 @@ func Benchmark_Sized32DenseIntersect(b *testing.B) {
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		Intersect32([]Set32{s1, s2})
+		Intersect32(s1, s2)
 	}
 }
 
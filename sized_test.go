@@ -1,7 +1,9 @@
 package intset
 
 import (
+	"math/bits"
 	"math/rand"
+	"slices"
 	"testing"
 )
 
@@ -63,7 +65,7 @@ func Test_Sized_IntersectsTwoSets(t *testing.T) {
 	s2.Set(3)
 	s2.Set(4)
 
-	s := Intersect([]Set{s1, s2})
+	s := Intersect(s1, s2)
 	AssertFalse(t, s.Exists(1))
 	AssertTrue(t, s.Exists(2))
 	AssertTrue(t, s.Exists(3))
@@ -83,7 +85,7 @@ func Test_Sized_UnionsTwoSets(t *testing.T) {
 		s2.Set(3)
 		s2.Set(4)
 
-		s := Union([]Set{s1, s2})
+		s := Union(s1, s2)
 		AssertTrue(t, s.Exists(1))
 		AssertTrue(t, s.Exists(2))
 		AssertTrue(t, s.Exists(3))
@@ -92,6 +94,215 @@ func Test_Sized_UnionsTwoSets(t *testing.T) {
 	}
 }
 
+func Test_Sized_RemoveKeepsBucketsSorted(t *testing.T) {
+	s := NewSized(4)
+	for i := 0; i < 8; i++ {
+		s.Set(i * 4)
+	}
+	AssertTrue(t, s.Remove(0))
+	for i := 1; i < 8; i++ {
+		AssertTrue(t, s.Exists(i*4))
+	}
+}
+
+func Test_Sized_MatchesOracle(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		AssertOracle(t, NewSized(64), seed, 512)
+		AssertOracle(t, NewSized(1000), seed, 1<<16)
+	}
+}
+
+func Test_Sized_AutoGrow(t *testing.T) {
+	s := NewSizedConfig(16, NewConfig().AutoGrow(4))
+	AssertEqual(t, len(s.buckets), 4)
+	for i := 0; i < 16; i++ {
+		s.Set(i)
+	}
+	AssertEqual(t, len(s.buckets), 4)
+	s.Set(16)
+	AssertEqual(t, len(s.buckets), 8)
+	for i := 17; i < 1000; i++ {
+		s.Set(i)
+	}
+	AssertEqual(t, len(s.buckets), 256)
+	AssertEqual(t, s.Len(), 1000)
+	for i := 0; i < 1000; i++ {
+		AssertTrue(t, s.Exists(i))
+	}
+}
+
+func Test_Sized_AutoShrink(t *testing.T) {
+	s := NewSizedConfig(1000, NewConfig().AutoShrink(1))
+	AssertEqual(t, len(s.buckets), 256)
+	for i := 0; i < 256; i++ {
+		s.Set(i * 7)
+	}
+	for i := 0; i < 250; i++ {
+		s.Remove(i * 7)
+	}
+	AssertEqual(t, len(s.buckets), 4)
+	AssertEqual(t, s.Len(), 6)
+	for i := 250; i < 256; i++ {
+		AssertTrue(t, s.Exists(i*7))
+	}
+	for i := 0; i < 6; i++ {
+		s.Remove(1750 + i*7)
+	}
+	AssertEqual(t, len(s.buckets), 1)
+	AssertEqual(t, s.Len(), 0)
+}
+
+func Test_Sized_Resize(t *testing.T) {
+	s := NewSized(16)
+	for i := 0; i < 100; i++ {
+		s.Set(i * 3)
+	}
+	s.Resize(400)
+	AssertEqual(t, len(s.buckets), 128)
+	s.Resize(4)
+	AssertEqual(t, len(s.buckets), 1)
+	AssertEqual(t, s.Len(), 100)
+	for i := 0; i < 100; i++ {
+		AssertTrue(t, s.Exists(i*3))
+		AssertFalse(t, s.Exists(i*3+1))
+	}
+}
+
+func Test_Sized_AutoResizeMatchesOracle(t *testing.T) {
+	config := NewConfig().AutoGrow(4).AutoShrink(1)
+	for seed := int64(0); seed < 10; seed++ {
+		AssertOracle(t, NewSizedConfig(4, config), seed, 1<<12)
+	}
+}
+
+func Test_Sized_BucketAlignedMatchesGeneric(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for round := 0; round < 50; round++ {
+		sets := make(Sets, 3)
+		for i := range sets {
+			s := NewSized(4 << r.Intn(6))
+			for j := 0; j < 150; j++ {
+				s.Set(r.Intn(400) - 200)
+			}
+			sets[i] = s
+		}
+		AssertTrue(t, Equal(Intersect(sets...), intersectGeneric(sets)))
+		AssertTrue(t, Equal(Union(sets...), unionGeneric(sets)))
+		AssertSorted(t, Intersect(sets...))
+		AssertSorted(t, Union(sets...))
+	}
+}
+
+func Test_Sized_IntersectMergesWiderBuckets(t *testing.T) {
+	narrow := NewSized(16)
+	wide := NewSized(1024)
+	for i := -300; i < 300; i++ {
+		narrow.Set(i * 3)
+		wide.Set(i * 5)
+	}
+	AssertTrue(t, narrow.mask < wide.mask)
+	for i, bucket := range narrow.buckets {
+		shift := bits.OnesCount64(narrow.mask)
+		values := wide.keepMerged(slices.Clone(bucket), shift, make([]int, wide.mask>>shift+1))
+		expected := slices.DeleteFunc(slices.Clone(bucket), func(value int) bool { return wide.Exists(value) == false })
+		AssertTrue(t, slices.Equal(values, expected))
+		AssertTrue(t, slices.Equal(Intersect(narrow, wide).buckets[i], expected))
+	}
+	AssertEqual(t, Intersect(wide, narrow).Len(), 120)
+}
+
+func Test_Sized_IntersectWithHasherUsesGeneric(t *testing.T) {
+	s1 := NewSizedConfig(10, NewConfig().Hasher(XXHash))
+	s2 := NewSized(100)
+	for i := 0; i < 20; i++ {
+		s1.Set(i)
+		s2.Set(i * 2)
+	}
+	AssertEqual(t, Intersect(s1, s2).Len(), 10)
+	AssertEqual(t, Union(s1, s2).Len(), 30)
+}
+
+// AssertSorted checks that every bucket is sorted and holds only its own values
+func AssertSorted[T Integer](t *testing.T, s *SizedOf[T]) {
+	t.Helper()
+	count := 0
+	for i, bucket := range s.buckets {
+		for j, value := range bucket {
+			AssertEqual(t, s.bucket(value), uint64(i))
+			AssertTrue(t, j == 0 || bucket[j-1] < value)
+		}
+		count += len(bucket)
+	}
+	AssertEqual(t, count, s.Len())
+}
+
+func Test_Sized_IntersectInto(t *testing.T) {
+	s1 := NewSized(100)
+	s2 := NewSized(10)
+	for i := 0; i < 100; i++ {
+		s1.Set(i)
+		if i%10 == 0 {
+			s2.Set(i)
+		}
+	}
+	sets := Sets{s1, s2}
+	values := IntersectInto([]int{-1}, 0, sets...)
+	AssertEqual(t, len(values), 11)
+	AssertEqual(t, values[0], -1)
+	AssertTrue(t, Equal(FromSliceOf(values[1:]), s2))
+
+	values = IntersectInto(nil, 3, sets...)
+	AssertEqual(t, len(values), 3)
+	values = IntersectInto(nil, 3, s1.Freeze(), s2.Freeze())
+	AssertEqual(t, len(values), 3)
+	AssertEqual(t, len(IntersectInto(nil, 0)), 0)
+
+	// sets were not reordered
+	AssertTrue(t, sets[0] == Set(s1))
+}
+
+func Test_Sized_IntersectIntoDoesNotAllocate(t *testing.T) {
+	s1 := NewSized(100)
+	s2 := NewSized(100)
+	for i := 0; i < 100; i++ {
+		s1.Set(i)
+		s2.Set(i * 2)
+	}
+	sets := Sets{s1, s2}
+	dst := make([]int, 0, 50)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = IntersectInto(dst[:0], 0, sets...)
+	})
+	AssertEqual(t, allocs, 0.0)
+	AssertEqual(t, len(dst), 50)
+}
+
+func Test_Sized_IntersectLeavesSetsUntouched(t *testing.T) {
+	s1 := NewSized(100)
+	s2 := NewSized(10)
+	for i := 0; i < 100; i++ {
+		s1.Set(i)
+	}
+	s2.Set(5)
+	for _, sets := range []Sets{{s1, s2}, {s1.Freeze(), s2.Freeze()}} {
+		first, second := sets[0], sets[1]
+		AssertEqual(t, Intersect(sets...).Len(), 1)
+		AssertEqual(t, Union(sets...).Len(), 100)
+		AssertTrue(t, sets[0] == first)
+		AssertTrue(t, sets[1] == second)
+	}
+}
+
+func Test_Sized_EmptyIntersectAndUnion(t *testing.T) {
+	for _, s := range []*Sized{Intersect(), Union(), Intersect(Sets{}...)} {
+		AssertEqual(t, s.Len(), 0)
+		s.Set(1)
+		AssertTrue(t, s.Exists(1))
+	}
+	AssertEqual(t, Intersect32().Len(), 0)
+	AssertEqual(t, UnionRune().Len(), 0)
+}
+
 func Test_Swap(t *testing.T) {
 	s1 := NewSized(1)
 	s1.Set(0)
@@ -105,6 +316,48 @@ func Test_Swap(t *testing.T) {
 	AssertFalse(t, s[1].Exists(1))
 }
 
+func Test_SizedOf_Int8(t *testing.T) {
+	s := NewSizedOf[int8](1000)
+	AssertEqual(t, len(s.buckets), 256)
+	for i := -128; i < 128; i++ {
+		AssertFalse(t, s.Exists(int8(i)))
+		s.Set(int8(i))
+		AssertTrue(t, s.Exists(int8(i)))
+	}
+	AssertEqual(t, s.Len(), 256)
+	AssertTrue(t, s.Remove(-128))
+	AssertFalse(t, s.Exists(-128))
+	AssertTrue(t, s.Exists(127))
+}
+
+func Test_SizedOf_Uint64(t *testing.T) {
+	s := NewSizedOf[uint64](10)
+	s.Set(1 << 63)
+	s.Set(1<<64 - 1)
+	AssertTrue(t, s.Exists(1<<63))
+	AssertTrue(t, s.Exists(1<<64-1))
+	AssertFalse(t, s.Exists(0))
+	AssertEqual(t, s.Len(), 2)
+}
+
+func Test_SizedOf_IntersectsAndUnions(t *testing.T) {
+	s1 := NewSizedOf[int16](10)
+	s2 := NewSizedOf[int16](10)
+	s1.Set(-1)
+	s1.Set(2)
+	s2.Set(2)
+	s2.Set(300)
+
+	i := IntersectOf[int16](s1, s2)
+	AssertEqual(t, i.Len(), 1)
+	AssertTrue(t, i.Exists(2))
+
+	u := UnionOf[int16](s1, s2)
+	AssertEqual(t, u.Len(), 3)
+	AssertTrue(t, u.Exists(-1))
+	AssertTrue(t, u.Exists(300))
+}
+
 func Benchmark_SizedPopulate(b *testing.B) {
 	s := NewSized(10000000)
 	b.ResetTimer()
@@ -152,8 +405,54 @@ func Benchmark_SizedDenseIntersect(b *testing.B) {
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		Intersect([]Set{s1, s2})
+		Intersect(s1, s2)
+	}
+}
+
+func Benchmark_SizedAlignedIntersect(b *testing.B) {
+	sets := alignedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Intersect(sets...)
+	}
+}
+
+func Benchmark_SizedGenericIntersect(b *testing.B) {
+	sets := alignedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		intersectGeneric(sets)
+	}
+}
+
+func Benchmark_SizedAlignedUnion(b *testing.B) {
+	sets := alignedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Union(sets...)
+	}
+}
+
+func Benchmark_SizedGenericUnion(b *testing.B) {
+	sets := alignedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		unionGeneric(sets)
+	}
+}
+
+// alignedSets returns two sets with the same mask and one with twice as many buckets
+func alignedSets() Sets {
+	sets := Sets{NewSized(100000), NewSized(100000), NewSized(200000)}
+	for _, set := range sets {
+		s := set.(*Sized)
+		for i := 0; i < 200000; i++ {
+			if rand.Intn(2) == 0 {
+				s.Set(i)
+			}
+		}
 	}
+	return sets
 }
 
 // Benchmarks for map[int]struct{}